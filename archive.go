@@ -0,0 +1,106 @@
+package stl
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ProcessArchive computes the bounding box of every *.stl entry in a zip or tar
+// archive read from r, keyed by entry name, without unpacking it to disk.
+// format is "zip" or "tar". Other entries are skipped. Zip archives need
+// random access: r is used directly if it implements io.ReaderAt and
+// io.Seeker, and is read into memory otherwise. The first entry that fails to
+// parse aborts processing.
+func ProcessArchive(r io.Reader, format string, opts ...Option) (map[string]*BoundingBox, error) {
+	switch strings.ToLower(format) {
+	case "zip":
+		return processZip(r, opts)
+	case "tar":
+		return processTar(r, opts)
+	default:
+		return nil, fmt.Errorf("invalid archive format %q: must be one of zip, tar", format)
+	}
+}
+
+// isSTLName reports whether an archive entry name has an .stl extension
+func isSTLName(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".stl")
+}
+
+// processZip computes the bounding boxes of the STL entries of a zip archive
+func processZip(r io.Reader, opts []Option) (map[string]*BoundingBox, error) {
+	ra, ok := r.(io.ReaderAt)
+	size, err := remainingSize(r)
+	if err != nil {
+		return nil, err
+	}
+	if ok && size >= 0 {
+		// size is measured from the current offset but ReadAt offsets are
+		// absolute, so read the archive as a section starting there
+		start, err := r.(io.Seeker).Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, fmt.Errorf("error seeking file: %w", err)
+		}
+		ra = io.NewSectionReader(ra, start, size)
+	} else {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("error reading archive: %w", err)
+		}
+		ra, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	archive, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive: %w", err)
+	}
+
+	boxes := make(map[string]*BoundingBox)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !isSTLName(file.Name) {
+			continue
+		}
+
+		entry, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("error opening %s: %w", file.Name, err)
+		}
+		bbox, err := CalculateBoundingBox(entry, opts...)
+		entry.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		boxes[file.Name] = bbox
+	}
+	return boxes, nil
+}
+
+// processTar computes the bounding boxes of the STL entries of a tar archive
+func processTar(r io.Reader, opts []Option) (map[string]*BoundingBox, error) {
+	archive := tar.NewReader(r)
+
+	boxes := make(map[string]*BoundingBox)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return boxes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !isSTLName(header.Name) {
+			continue
+		}
+
+		bbox, err := CalculateBoundingBox(archive, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", header.Name, err)
+		}
+		boxes[header.Name] = bbox
+	}
+}
@@ -0,0 +1,115 @@
+package stl
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// tarEntry is a named file for tarArchive
+type tarEntry struct {
+	name string
+	data []byte
+}
+
+// tarArchive returns a tar archive holding the given entries in order, with
+// names ending in "/" written as directories
+func tarArchive(t *testing.T, entries []tarEntry) []byte {
+	t.Helper()
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0o644, Size: int64(len(entry.data)), Typeflag: tar.TypeReg}
+		if entry.name[len(entry.name)-1] == '/' {
+			header.Typeflag, header.Mode = tar.TypeDir, 0o755
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("WriteHeader: %v", err)
+		}
+		if _, err := tw.Write(entry.data); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return archive.Bytes()
+}
+
+func TestProcessTar(t *testing.T) {
+	cube := unitCube()
+	shifted := translate(strip(3), r3.Vec{X: -4, Y: 2})
+
+	data := tarArchive(t, []tarEntry{
+		{"parts/", nil},
+		{"parts/cube.stl", binarySTL(t, cube)},
+		{"README.txt", []byte("solid not really")},
+		{"strip.STL", asciiSTL(t, "strip", shifted)},
+	})
+	boxes, err := ProcessArchive(bytes.NewReader(data), "TAR")
+	if err != nil {
+		t.Fatalf("ProcessArchive: %v", err)
+	}
+	want := map[string]*BoundingBox{
+		"parts/cube.stl": BoundingBoxFromTriangles(cube),
+		"strip.STL":      BoundingBoxFromTriangles(shifted),
+	}
+	if len(boxes) != len(want) {
+		t.Errorf("got %d boxes, want %d: %v", len(boxes), len(want), boxes)
+	}
+	for name, bbox := range want {
+		if got, ok := boxes[name]; !ok || !got.Equal(bbox) {
+			t.Errorf("%s: got box %v, want %v", name, got, bbox)
+		}
+	}
+
+	// A broken entry aborts processing
+	data = tarArchive(t, []tarEntry{
+		{"cube.stl", binarySTL(t, cube)},
+		{"broken.stl", []byte("solid broken\nfacet normal x\n")},
+	})
+	if _, err := ProcessArchive(bytes.NewReader(data), "tar"); err == nil {
+		t.Errorf("ProcessArchive with a broken entry: got no error")
+	}
+
+	if _, err := ProcessArchive(bytes.NewReader(data), "rar"); err == nil {
+		t.Errorf("ProcessArchive with format rar: got no error")
+	}
+}
+
+func TestProcessZipAtOffset(t *testing.T) {
+	cube := unitCube()
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, err := zw.Create("parts/cube.stl")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := w.Write(binarySTL(t, cube)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The archive follows a prefix the caller has already read past
+	prefix := bytes.Repeat([]byte{0xff}, 100)
+	r := bytes.NewReader(append(prefix, archive.Bytes()...))
+	if _, err := r.Seek(int64(len(prefix)), io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+
+	boxes, err := ProcessArchive(r, "zip")
+	if err != nil {
+		t.Fatalf("ProcessArchive: %v", err)
+	}
+	if want := BoundingBoxFromTriangles(cube); len(boxes) != 1 || !boxes["parts/cube.stl"].Equal(want) {
+		t.Errorf("got boxes %v, want the cube's box for parts/cube.stl", boxes)
+	}
+}
@@ -0,0 +1,474 @@
+package stl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// asciiTokenizer splits an ASCII STL file into whitespace-separated tokens.
+// Unlike bufio.Scanner it has no line length limit, so facets may be laid out
+// across lines arbitrarily, including an entire file on a single line.
+type asciiTokenizer struct {
+	r *bufio.Reader
+	// line is the 1-based line number of the most recently returned token
+	line int
+	// pending holds tokens pushed back to be returned before reading more input
+	pending []string
+	buf     []byte
+	started bool
+}
+
+// newASCIITokenizer returns a tokenizer reading from r
+func newASCIITokenizer(r io.Reader) *asciiTokenizer {
+	return &asciiTokenizer{r: bufio.NewReader(r), line: 1}
+}
+
+// next returns the next token, or io.EOF once the input is exhausted
+func (t *asciiTokenizer) next() (string, error) {
+	if n := len(t.pending); n > 0 {
+		token := t.pending[0]
+		t.pending = t.pending[1:]
+		return token, nil
+	}
+
+	if err := t.skipSpace(); err != nil {
+		return "", err
+	}
+
+	t.buf = t.buf[:0]
+	for {
+		c, err := t.r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error reading file: %w", err)
+		}
+		if isSpace(c) {
+			t.r.UnreadByte()
+			break
+		}
+		t.buf = append(t.buf, c)
+	}
+	return string(t.buf), nil
+}
+
+// restOfLine returns the remainder of the current line with surrounding
+// whitespace trimmed, consuming the line ending
+func (t *asciiTokenizer) restOfLine() (string, error) {
+	// Pushed-back tokens are the remainder of a line split by solidName, as
+	// in single-line files
+	if len(t.pending) > 0 {
+		rest := strings.Join(t.pending, " ")
+		t.pending = nil
+		return rest, nil
+	}
+
+	t.buf = t.buf[:0]
+	for {
+		c, err := t.r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error reading file: %w", err)
+		}
+		if c == '\n' || c == '\r' {
+			t.r.UnreadByte()
+			break
+		}
+		t.buf = append(t.buf, c)
+	}
+	return strings.TrimSpace(string(t.buf)), nil
+}
+
+// push returns tokens to be read again before any further input
+func (t *asciiTokenizer) push(tokens ...string) {
+	t.pending = append(tokens, t.pending...)
+}
+
+// skipSpace consumes whitespace, a leading byte order mark, and line endings,
+// counting lines as it goes
+func (t *asciiTokenizer) skipSpace() error {
+	if !t.started {
+		t.started = true
+		if bom, err := t.r.Peek(len(utf8BOM)); err == nil && string(bom) == string(utf8BOM) {
+			t.r.Discard(len(utf8BOM))
+		}
+	}
+
+	for {
+		c, err := t.r.ReadByte()
+		if err == io.EOF {
+			return io.EOF
+		}
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+		switch {
+		case c == '\n':
+			t.line++
+		case c == '\r':
+			// Count CRLF once, and a lone CR as a line ending
+			if next, err := t.r.Peek(1); err != nil || next[0] != '\n' {
+				t.line++
+			}
+		case !isSpace(c):
+			t.r.UnreadByte()
+			return nil
+		}
+	}
+}
+
+// isSpace reports whether c is an ASCII whitespace character
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// solidName reads the name following a "solid" or, if afterEnd is set, an
+// "endsolid" keyword: the rest of its line with surrounding whitespace trimmed,
+// so names with spaces and non-ASCII characters are preserved. Words that are
+// also keywords stay part of the name unless they start the next part of a
+// single-line file, in which case that part is pushed back.
+func (t *asciiTokenizer) solidName(afterEnd bool) (string, error) {
+	rest, err := t.restOfLine()
+	if err != nil {
+		return "", err
+	}
+
+	// Find whitespace-separated fields by byte offset; UTF-8 continuation
+	// bytes never match ASCII whitespace
+	var starts []int
+	var fields []string
+	for start := 0; start < len(rest); {
+		if isSpace(rest[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(rest) && !isSpace(rest[end]) {
+			end++
+		}
+		starts = append(starts, start)
+		fields = append(fields, rest[start:end])
+		start = end
+	}
+
+	for i := range fields {
+		if startsSolidContent(fields, i, afterEnd) {
+			t.push(fields[i:]...)
+			return strings.TrimSpace(rest[:starts[i]]), nil
+		}
+	}
+	return rest, nil
+}
+
+// startsSolidContent reports whether fields[i] begins the content that
+// follows a solid name on the same line: "facet normal", or an "endsolid"
+// closing a solid with no facets, optionally repeating the name and followed
+// by the next solid. After an endsolid, it is instead a "solid" keyword that
+// opens another single-line solid.
+func startsSolidContent(fields []string, i int, afterEnd bool) bool {
+	isFacet := func(j int) bool {
+		return strings.EqualFold(fields[j], "facet") && j+1 < len(fields) && strings.EqualFold(fields[j+1], "normal")
+	}
+
+	if isFacet(i) {
+		return true
+	}
+	if afterEnd {
+		if !strings.EqualFold(fields[i], "solid") {
+			return false
+		}
+		for j := i + 1; j < len(fields); j++ {
+			if isFacet(j) || strings.EqualFold(fields[j], "endsolid") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !strings.EqualFold(fields[i], "endsolid") {
+		return false
+	}
+	name, after := fields[:i], fields[i+1:]
+	if len(after) == 0 {
+		return true
+	}
+	if len(after) < len(name) || !slices.Equal(after[:len(name)], name) {
+		return false
+	}
+	return len(after) == len(name) || strings.EqualFold(after[len(name)], "solid")
+}
+
+// isKeyword reports whether token is an ASCII STL keyword, ignoring case
+func isKeyword(token string) bool {
+	switch strings.ToLower(token) {
+	case "solid", "endsolid", "facet", "normal", "endfacet", "outer", "loop", "endloop", "vertex":
+		return true
+	}
+	return false
+}
+
+// readVec reads three coordinate tokens into an r3.Vec
+func (t *asciiTokenizer) readVec() (r3.Vec, error) {
+	// Report errors at the line of the keyword preceding the coordinates
+	line := t.line
+
+	var fields [3]string
+	for i := range fields {
+		token, err := t.next()
+		if err == io.EOF {
+			return r3.Vec{}, fmt.Errorf("%w: line %d: expected 3 coordinates, got %d", ErrInvalidVertex, line, i)
+		}
+		if err != nil {
+			return r3.Vec{}, err
+		}
+		// A keyword means coordinates are missing; leave it for the caller
+		if isKeyword(token) {
+			t.push(token)
+			return r3.Vec{}, fmt.Errorf("%w: line %d: expected 3 coordinates, got %d", ErrInvalidVertex, line, i)
+		}
+		fields[i] = token
+	}
+
+	vec, err := parseVec(fields[:])
+	if err != nil {
+		return r3.Vec{}, fmt.Errorf("%w: line %d: %w", ErrInvalidVertex, line, err)
+	}
+	return vec, nil
+}
+
+// parseASCII parses an ASCII STL file. Parsing stops at an endsolid that is
+// not followed by another solid, ignoring any trailing content. With
+// WithSkipInvalid, malformed facets are recorded as warnings and skipped
+// instead of aborting the parse.
+func parseASCII(r io.Reader, cfg *config, v *visitor) error {
+	tokens := newASCIITokenizer(r)
+	if cfg.consumed != nil {
+		// Bytes left in the tokenizer's buffer were read ahead, not consumed
+		defer func() { cfg.consumed.n -= int64(tokens.r.Buffered()) }()
+	}
+
+	var currentTriangle Triangle
+	vertexIndex := 0
+	inFacet := false
+	inSolid := false
+	// skipFacet is set once the current facet is known to be malformed
+	skipFacet := false
+	total := 0
+
+	// line is the line of the most recent keyword, where problems are reported
+	line := 0
+
+	// invalid returns err, or records it as a warning and skips the current
+	// facet in lenient mode
+	invalid := func(err error) error {
+		if !cfg.skipInvalid || !errors.Is(err, ErrInvalidVertex) {
+			return err
+		}
+		cfg.addWarning(Warning{Line: line, Err: err})
+		skipFacet = inFacet
+		return nil
+	}
+
+tokenLoop:
+	for {
+		token, err := tokens.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line = tokens.line
+
+		// Keywords are matched case-insensitively, as "FACET" and "Vertex" are
+		// common in real-world files
+		switch strings.ToLower(token) {
+		case "solid":
+			name, err := tokens.solidName(false)
+			if err != nil {
+				return err
+			}
+			v.beginSolid(name, -1)
+			inSolid = true
+		case "endsolid":
+			// The name after endsolid is informational only
+			if _, err := tokens.solidName(true); err != nil {
+				return err
+			}
+			inSolid = false
+
+			// Only another solid may follow; anything else, such as exporter
+			// log text or repeated endsolid lines, is trailing junk
+			next, err := tokens.next()
+			if err == io.EOF {
+				break tokenLoop
+			}
+			if err != nil {
+				return err
+			}
+			if !strings.EqualFold(next, "solid") {
+				break tokenLoop
+			}
+			tokens.push(next)
+		case "facet":
+			if err := cfg.ctx.Err(); err != nil {
+				return err
+			}
+
+			inFacet = true
+			skipFacet = false
+			vertexIndex = 0
+			currentTriangle = Triangle{}
+
+			// "facet normal nx ny nz"
+			next, err := tokens.next()
+			if err != nil && err != io.EOF {
+				return err
+			}
+			if strings.EqualFold(next, "normal") {
+				normal, err := tokens.readVec()
+				if err != nil {
+					if err := invalid(fmt.Errorf("error parsing facet normal: %w", err)); err != nil {
+						return err
+					}
+				}
+				currentTriangle.Normal = normal
+			} else if err == nil {
+				tokens.push(next)
+			}
+		case "vertex":
+			if skipFacet {
+				continue
+			}
+			if !inFacet {
+				if err := invalid(fmt.Errorf("%w: line %d: vertex outside of a facet", ErrInvalidVertex, line)); err != nil {
+					return err
+				}
+				continue
+			}
+			if vertexIndex >= 3 {
+				if err := invalid(fmt.Errorf("%w: line %d: too many vertices in facet", ErrInvalidVertex, line)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			vertex, err := tokens.readVec()
+			if err != nil {
+				if err := invalid(err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			currentTriangle.Vertices[vertexIndex] = vertex
+			vertexIndex++
+		case "endfacet":
+			if skipFacet {
+				inFacet = false
+				skipFacet = false
+				continue
+			}
+			if !inFacet || vertexIndex != 3 {
+				if err := invalid(fmt.Errorf("%w: line %d: incomplete triangle, got %d vertices", ErrInvalidVertex, line, vertexIndex)); err != nil {
+					return err
+				}
+				inFacet = false
+				skipFacet = false
+				continue
+			}
+			if cfg.exceedsMaxTriangles(total + 1) {
+				return fmt.Errorf("file contains more than %d triangles", cfg.maxTriangles)
+			}
+			// Facets outside a solid block belong to an unnamed solid
+			if !inSolid {
+				v.beginSolid("", -1)
+				inSolid = true
+			}
+			inFacet = false
+			accepted, err := visitTriangle(v, currentTriangle)
+			if err != nil {
+				return err
+			}
+			if !accepted {
+				continue
+			}
+			total++
+
+			if total%checkInterval == 0 {
+				cfg.reportProgress(total, -1)
+			}
+		case "outer", "loop", "endloop":
+			// Vertex loops are optional; the vertices alone define the facet
+		}
+	}
+
+	// Check if we found any triangles
+	if total == 0 {
+		return fmt.Errorf("%w: no triangles found in STL file", ErrEmptyMesh)
+	}
+
+	cfg.reportProgress(total, -1)
+	return nil
+}
+
+// countASCIIFacets returns the number of "endfacet" keywords in an ASCII STL
+// file, up to the end of its last solid
+func countASCIIFacets(r io.Reader) (int, error) {
+	tokens := newASCIITokenizer(r)
+	count := 0
+	for {
+		token, err := tokens.next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		switch strings.ToLower(token) {
+		case "endfacet":
+			count++
+		case "endsolid":
+			// Stop where parseASCII would, ignoring trailing content
+			if _, err := tokens.solidName(true); err != nil {
+				return 0, err
+			}
+			next, err := tokens.next()
+			if err == io.EOF || (err == nil && !strings.EqualFold(next, "solid")) {
+				return count, nil
+			}
+			if err != nil {
+				return 0, err
+			}
+			tokens.push(next)
+		}
+	}
+}
+
+// parseVec parses three coordinate fields into an r3.Vec
+func parseVec(fields []string) (r3.Vec, error) {
+	x, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return r3.Vec{}, fmt.Errorf("error parsing x coordinate: %w", err)
+	}
+	y, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return r3.Vec{}, fmt.Errorf("error parsing y coordinate: %w", err)
+	}
+	z, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return r3.Vec{}, fmt.Errorf("error parsing z coordinate: %w", err)
+	}
+
+	return r3.Vec{X: x, Y: y, Z: z}, nil
+}
@@ -0,0 +1,56 @@
+package stl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// BinaryTriangleReader reads the raw 50-byte triangle records of a binary STL
+// file one at a time, keeping the float32 values and attribute bytes exactly
+// as stored. It reads from the first record, so the 80-byte header and
+// triangle count must already have been consumed.
+type BinaryTriangleReader struct {
+	r     io.Reader
+	order binary.ByteOrder
+	// index is the index of the next record
+	index  int
+	record [50]byte
+}
+
+// NewBinaryTriangleReader returns a reader decoding records from r in the
+// given byte order, or little-endian if order is nil
+func NewBinaryTriangleReader(r io.Reader, order binary.ByteOrder) *BinaryTriangleReader {
+	if order == nil {
+		order = binary.LittleEndian
+	}
+	return &BinaryTriangleReader{r: r, order: order}
+}
+
+// Next returns the next triangle record and its attribute byte count. It
+// returns io.EOF once the input ends cleanly between records, and an error
+// wrapping ErrTruncated if it ends partway through one.
+func (br *BinaryTriangleReader) Next() (BinaryTriangle, uint16, error) {
+	if _, err := io.ReadFull(br.r, br.record[:]); err != nil {
+		if err == io.EOF {
+			return BinaryTriangle{}, 0, io.EOF
+		}
+		return BinaryTriangle{}, 0, readError(fmt.Sprintf("error reading triangle %d", br.index), err)
+	}
+	br.index++
+
+	var t BinaryTriangle
+	for k := 0; k < 3; k++ {
+		t.Normal[k] = br.float32(k)
+		for j := 0; j < 3; j++ {
+			t.Vertices[j][k] = br.float32(3 + 3*j + k)
+		}
+	}
+	return t, br.order.Uint16(br.record[48:]), nil
+}
+
+// float32 decodes the i-th float32 value of the current record
+func (br *BinaryTriangleReader) float32(i int) float32 {
+	return math.Float32frombits(br.order.Uint32(br.record[4*i:]))
+}
@@ -0,0 +1,57 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestBinaryTriangleReader(t *testing.T) {
+	triangles := strip(3)
+	data := binarySTL(t, triangles)
+	// Give the second record a nonzero attribute byte count, as color
+	// extensions do
+	binary.LittleEndian.PutUint16(data[84+50+48:], 0x8421)
+	want := []uint16{0, 0x8421, 0}
+
+	for _, tt := range []struct {
+		name  string
+		data  []byte
+		order binary.ByteOrder
+	}{
+		{"little endian", data, nil},
+		{"big endian", bigEndian(data), binary.BigEndian},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			br := NewBinaryTriangleReader(bytes.NewReader(tt.data[84:]), tt.order)
+			for i, tri := range triangles {
+				got, attribute, err := br.Next()
+				if err != nil {
+					t.Fatalf("Next %d: %v", i, err)
+				}
+				for j, v := range tri.Vertices {
+					if w := [3]float32{float32(v.X), float32(v.Y), float32(v.Z)}; got.Vertices[j] != w {
+						t.Errorf("triangle %d vertex %d = %v, want %v", i, j, got.Vertices[j], w)
+					}
+				}
+				if attribute != want[i] {
+					t.Errorf("triangle %d attribute = %#x, want %#x", i, attribute, want[i])
+				}
+			}
+			if _, _, err := br.Next(); err != io.EOF {
+				t.Errorf("after the last record: got error %v, want io.EOF", err)
+			}
+		})
+	}
+
+	br := NewBinaryTriangleReader(bytes.NewReader(data[84:len(data)-10]), nil)
+	var err error
+	for err == nil {
+		_, _, err = br.Next()
+	}
+	if !errors.Is(err, ErrTruncated) {
+		t.Errorf("truncated: got error %v, want ErrTruncated", err)
+	}
+}
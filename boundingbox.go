@@ -0,0 +1,294 @@
+package stl
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// IsEmpty reports whether the bounding box contains no points, i.e. its min
+// exceeds its max on some axis. Boxes that have not been updated with any
+// geometry are empty. Note that the zero value BoundingBox is not empty: it is
+// a single point at the origin.
+func (bb *BoundingBox) IsEmpty() bool {
+	return bb.MinX > bb.MaxX || bb.MinY > bb.MaxY || bb.MinZ > bb.MaxZ
+}
+
+// Reset empties the bounding box so it can be reused: mins are set to
+// +math.MaxFloat32, maxes to -math.MaxFloat32, and the center to zero.
+func (bb *BoundingBox) Reset() {
+	*bb = BoundingBox{
+		MinX: math.MaxFloat32, MinY: math.MaxFloat32, MinZ: math.MaxFloat32,
+		MaxX: -math.MaxFloat32, MaxY: -math.MaxFloat32, MaxZ: -math.MaxFloat32,
+	}
+}
+
+// Union returns a new bounding box spanning both bb and other.
+// Empty or nil boxes are ignored. Callers must pass boxes computed from
+// geometry; a zero value BoundingBox would extend the result to the origin.
+func (bb *BoundingBox) Union(other *BoundingBox) *BoundingBox {
+	return UnionAll(bb, other)
+}
+
+// UnionAll returns a new bounding box spanning all of the given boxes.
+// Empty or nil boxes are ignored; if every box is empty, the result is empty.
+func UnionAll(boxes ...*BoundingBox) *BoundingBox {
+	result := newBoundingBox()
+
+	for _, box := range boxes {
+		if box == nil || box.IsEmpty() {
+			continue
+		}
+
+		result.MinX = min(result.MinX, box.MinX)
+		result.MinY = min(result.MinY, box.MinY)
+		result.MinZ = min(result.MinZ, box.MinZ)
+		result.MaxX = max(result.MaxX, box.MaxX)
+		result.MaxY = max(result.MaxY, box.MaxY)
+		result.MaxZ = max(result.MaxZ, box.MaxZ)
+	}
+
+	updateCenter(result)
+	return result
+}
+
+// Contains reports whether p lies within the bounding box, inclusive of its faces
+func (bb *BoundingBox) Contains(p r3.Vec) bool {
+	return p.X >= float64(bb.MinX) && p.X <= float64(bb.MaxX) &&
+		p.Y >= float64(bb.MinY) && p.Y <= float64(bb.MaxY) &&
+		p.Z >= float64(bb.MinZ) && p.Z <= float64(bb.MaxZ)
+}
+
+// ContainsBox reports whether other lies entirely within the bounding box,
+// inclusive of its faces
+func (bb *BoundingBox) ContainsBox(other *BoundingBox) bool {
+	return other.MinX >= bb.MinX && other.MaxX <= bb.MaxX &&
+		other.MinY >= bb.MinY && other.MaxY <= bb.MaxY &&
+		other.MinZ >= bb.MinZ && other.MaxZ <= bb.MaxZ
+}
+
+// Intersects reports whether the bounding box overlaps other.
+// Boxes that only touch at a face, edge, or corner intersect.
+func (bb *BoundingBox) Intersects(other *BoundingBox) bool {
+	return bb.MinX <= other.MaxX && bb.MaxX >= other.MinX &&
+		bb.MinY <= other.MaxY && bb.MaxY >= other.MinY &&
+		bb.MinZ <= other.MaxZ && bb.MaxZ >= other.MinZ
+}
+
+// Extents returns the width, height, and depth of the bounding box as an
+// array indexed by axis (0 = X, 1 = Y, 2 = Z)
+func (bb *BoundingBox) Extents() [3]float64 {
+	w, h, d := bb.Dimensions()
+	return [3]float64{float64(w), float64(h), float64(d)}
+}
+
+// MinVec returns the minimum corner of the bounding box
+func (bb *BoundingBox) MinVec() r3.Vec {
+	return r3.Vec{X: float64(bb.MinX), Y: float64(bb.MinY), Z: float64(bb.MinZ)}
+}
+
+// MaxVec returns the maximum corner of the bounding box
+func (bb *BoundingBox) MaxVec() r3.Vec {
+	return r3.Vec{X: float64(bb.MaxX), Y: float64(bb.MaxY), Z: float64(bb.MaxZ)}
+}
+
+// Diagonal returns the length of the box's space diagonal, from its minimum
+// to its maximum corner
+func (bb *BoundingBox) Diagonal() float64 {
+	return r3.Norm(r3.Sub(bb.MaxVec(), bb.MinVec()))
+}
+
+// LongestAxis returns the axis (0 = X, 1 = Y, 2 = Z) along which the box is
+// longest, and its length along that axis. Ties favor the lower axis.
+func (bb *BoundingBox) LongestAxis() (axis int, length float64) {
+	extents := bb.Extents()
+	for i := 1; i < len(extents); i++ {
+		if extents[i] > extents[axis] {
+			axis = i
+		}
+	}
+	return axis, extents[axis]
+}
+
+// AspectRatio returns the ratios of the largest and second largest dimensions
+// of the box to its smallest, so a cube yields (1, 1) and a sheet a large
+// first ratio. A zero smallest dimension yields +Inf for nonzero dimensions.
+func (bb *BoundingBox) AspectRatio() (largest, middle float32) {
+	dims := sortedDimensions(bb)
+	return dimensionRatio(dims[2], dims[0]), dimensionRatio(dims[1], dims[0])
+}
+
+// IsFlat reports whether the smallest dimension of the box is below tol times
+// its largest, e.g. 0.05 for sheet-like parts. Empty boxes are not flat.
+func (bb *BoundingBox) IsFlat(tol float32) bool {
+	if bb.IsEmpty() {
+		return false
+	}
+	dims := sortedDimensions(bb)
+	return dims[0] < tol*dims[2]
+}
+
+// sortedDimensions returns the dimensions of bb in ascending order
+func sortedDimensions(bb *BoundingBox) [3]float32 {
+	w, h, d := bb.Dimensions()
+	dims := [3]float32{w, h, d}
+	sort.Slice(dims[:], func(i, j int) bool { return dims[i] < dims[j] })
+	return dims
+}
+
+// dimensionRatio returns a/b, treating equal dimensions, including two zero
+// ones, as a ratio of 1
+func dimensionRatio(a, b float32) float32 {
+	if a == b {
+		return 1
+	}
+	return a / b
+}
+
+// Expand returns a new bounding box grown by margin on every side. A negative
+// margin shrinks the box; each axis that would invert collapses to its
+// midpoint instead. Empty boxes are returned unchanged.
+func (bb *BoundingBox) Expand(margin float32) *BoundingBox {
+	m := float64(margin)
+	return bb.ExpandVec(r3.Vec{X: m, Y: m, Z: m})
+}
+
+// ExpandVec is like Expand but with a separate margin for each axis
+func (bb *BoundingBox) ExpandVec(margin r3.Vec) *BoundingBox {
+	if bb.IsEmpty() {
+		expanded := *bb
+		return &expanded
+	}
+
+	minX, maxX := expandRange(bb.MinX, bb.MaxX, float32(margin.X))
+	minY, maxY := expandRange(bb.MinY, bb.MaxY, float32(margin.Y))
+	minZ, maxZ := expandRange(bb.MinZ, bb.MaxZ, float32(margin.Z))
+
+	expanded := &BoundingBox{
+		MinX: minX, MinY: minY, MinZ: minZ,
+		MaxX: maxX, MaxY: maxY, MaxZ: maxZ,
+	}
+	updateCenter(expanded)
+	return expanded
+}
+
+// expandRange grows a range by margin at both ends, collapsing it to its
+// midpoint if a negative margin would make lo exceed hi
+func expandRange(lo, hi, margin float32) (float32, float32) {
+	lo, hi = lo-margin, hi+margin
+	if lo > hi {
+		mid := (lo + hi) / 2
+		return mid, mid
+	}
+	return lo, hi
+}
+
+// Corners returns the eight corners of the bounding box. Corner i takes its X
+// from MaxX if bit 0 of i is set and MinX otherwise, its Y from bit 1, and its
+// Z from bit 2, so Corners()[0] is the minimum corner and Corners()[7] the
+// maximum. Corners whose indices differ in exactly one bit share an edge.
+func (bb *BoundingBox) Corners() [8]r3.Vec {
+	lo, hi := bb.MinVec(), bb.MaxVec()
+
+	var corners [8]r3.Vec
+	for i := range corners {
+		corners[i] = lo
+		if i&1 != 0 {
+			corners[i].X = hi.X
+		}
+		if i&2 != 0 {
+			corners[i].Y = hi.Y
+		}
+		if i&4 != 0 {
+			corners[i].Z = hi.Z
+		}
+	}
+	return corners
+}
+
+// Equal reports whether bb and other have exactly the same bounds and center.
+// Two nil boxes are equal.
+func (bb *BoundingBox) Equal(other *BoundingBox) bool {
+	if bb == nil || other == nil {
+		return bb == other
+	}
+	return *bb == *other
+}
+
+// ApproxEqual reports whether each bound and center coordinate of bb is
+// within eps of the corresponding value of other. It tolerates the float32
+// rounding that can differ between parsing paths. Two nil boxes are equal.
+func (bb *BoundingBox) ApproxEqual(other *BoundingBox, eps float32) bool {
+	if bb == nil || other == nil {
+		return bb == other
+	}
+
+	within := func(a, b float64) bool {
+		return math.Abs(a-b) <= float64(eps)
+	}
+	return within(float64(bb.MinX), float64(other.MinX)) &&
+		within(float64(bb.MinY), float64(other.MinY)) &&
+		within(float64(bb.MinZ), float64(other.MinZ)) &&
+		within(float64(bb.MaxX), float64(other.MaxX)) &&
+		within(float64(bb.MaxY), float64(other.MaxY)) &&
+		within(float64(bb.MaxZ), float64(other.MaxZ)) &&
+		within(bb.Center.X, other.Center.X) &&
+		within(bb.Center.Y, other.Center.Y) &&
+		within(bb.Center.Z, other.Center.Z)
+}
+
+// InchesToMillimeters is the factor that converts inches to millimeters
+const InchesToMillimeters = 25.4
+
+// ScaleBoundingBox returns a new bounding box with every coordinate of bb
+// multiplied by factor about the origin. The center is scaled along with
+// the bounds, and the volume changes by the cube of factor. For the most
+// precise results, scale vertices during parsing with WithScale instead.
+// Empty boxes are returned unchanged.
+func ScaleBoundingBox(bb *BoundingBox, factor float64) *BoundingBox {
+	if bb.IsEmpty() {
+		scaled := *bb
+		return &scaled
+	}
+
+	minX, maxX := scaleRange(bb.MinX, bb.MaxX, factor)
+	minY, maxY := scaleRange(bb.MinY, bb.MaxY, factor)
+	minZ, maxZ := scaleRange(bb.MinZ, bb.MaxZ, factor)
+
+	scaled := &BoundingBox{
+		MinX: minX, MinY: minY, MinZ: minZ,
+		MaxX: maxX, MaxY: maxY, MaxZ: maxZ,
+	}
+	updateCenter(scaled)
+	return scaled
+}
+
+// ScaleAboutCenter returns a new bounding box with the half-extents of bb
+// multiplied by factor while its center stays fixed, e.g. 1.05 for a 5%
+// clearance envelope. The volume changes by the cube of factor. Negative
+// factors are treated as their absolute value, and empty boxes are returned
+// unchanged.
+func (bb *BoundingBox) ScaleAboutCenter(factor float32) *BoundingBox {
+	scaled := *bb
+	if bb.IsEmpty() {
+		return &scaled
+	}
+
+	f := math.Abs(float64(factor))
+	scaled.MinX, scaled.MaxX = scaleAbout(bb.MinX, bb.MaxX, bb.Center.X, f)
+	scaled.MinY, scaled.MaxY = scaleAbout(bb.MinY, bb.MaxY, bb.Center.Y, f)
+	scaled.MinZ, scaled.MaxZ = scaleAbout(bb.MinZ, bb.MaxZ, bb.Center.Z, f)
+	return &scaled
+}
+
+// scaleAbout scales the distance of both ends of a range from center by factor
+func scaleAbout(lo, hi float32, center, factor float64) (float32, float32) {
+	return float32(center - (center-float64(lo))*factor), float32(center + (float64(hi)-center)*factor)
+}
+
+// scaleRange scales both ends of a range, keeping lo <= hi for negative factors
+func scaleRange(lo, hi float32, factor float64) (float32, float32) {
+	a, b := float32(float64(lo)*factor), float32(float64(hi)*factor)
+	return min(a, b), max(a, b)
+}
@@ -0,0 +1,73 @@
+package stl
+
+import (
+	"io"
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// BoundingBoxF64 stores the min and max coordinates of a 3D model in double
+// precision. Unlike BoundingBox, coordinates are never truncated to float32,
+// which avoids visible rounding on large models far from the origin.
+type BoundingBoxF64 struct {
+	Min, Max r3.Vec
+	Center   r3.Vec
+}
+
+// Dimensions returns the width, height, and depth of the bounding box as a vector
+func (bb *BoundingBoxF64) Dimensions() r3.Vec {
+	return r3.Sub(bb.Max, bb.Min)
+}
+
+// Volume returns the volume of the bounding box
+func (bb *BoundingBoxF64) Volume() float64 {
+	d := bb.Dimensions()
+	return d.X * d.Y * d.Z
+}
+
+// CalculateBoundingBoxF64 reads an STL file from the given io.Reader
+// and returns its bounding box in double precision. Like CalculateBoundingBox,
+// it streams the triangles rather than loading the mesh into memory.
+func CalculateBoundingBoxF64(r io.Reader, opts ...Option) (*BoundingBoxF64, error) {
+	bbox := newBoundingBoxF64()
+	err := ForEachTriangle(r, func(t Triangle) error {
+		bbox.update(t.Vertices[:])
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	bbox.Center = r3.Scale(0.5, r3.Add(bbox.Min, bbox.Max))
+	return bbox, nil
+}
+
+// boundingBoxF64FromTriangles computes the double-precision bounding box of the given triangles
+func boundingBoxF64FromTriangles(triangles []Triangle) *BoundingBoxF64 {
+	bbox := newBoundingBoxF64()
+	for i := range triangles {
+		bbox.update(triangles[i].Vertices[:])
+	}
+
+	// Calculate center
+	bbox.Center = r3.Scale(0.5, r3.Add(bbox.Min, bbox.Max))
+
+	return bbox
+}
+
+// newBoundingBoxF64 returns an empty double-precision bounding box ready to be updated
+func newBoundingBoxF64() *BoundingBoxF64 {
+	return &BoundingBoxF64{
+		Min: r3.Vec{X: math.MaxFloat64, Y: math.MaxFloat64, Z: math.MaxFloat64},
+		Max: r3.Vec{X: -math.MaxFloat64, Y: -math.MaxFloat64, Z: -math.MaxFloat64},
+	}
+}
+
+// update extends the bounding box to include the given vertices
+func (bb *BoundingBoxF64) update(vertices []r3.Vec) {
+	for _, v := range vertices {
+		bb.Min = r3.Vec{X: math.Min(bb.Min.X, v.X), Y: math.Min(bb.Min.Y, v.Y), Z: math.Min(bb.Min.Z, v.Z)}
+		bb.Max = r3.Vec{X: math.Max(bb.Max.X, v.X), Y: math.Max(bb.Max.Y, v.Y), Z: math.Max(bb.Max.Z, v.Z)}
+	}
+}
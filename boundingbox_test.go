@@ -0,0 +1,193 @@
+package stl
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// box returns the bounding box spanning lo to hi with its center set
+func box(lo, hi r3.Vec) *BoundingBox {
+	bb := &BoundingBox{
+		MinX: float32(lo.X), MinY: float32(lo.Y), MinZ: float32(lo.Z),
+		MaxX: float32(hi.X), MaxY: float32(hi.Y), MaxZ: float32(hi.Z),
+	}
+	updateCenter(bb)
+	return bb
+}
+
+func TestContains(t *testing.T) {
+	unit := box(r3.Vec{}, r3.Vec{X: 1, Y: 1, Z: 1})
+
+	tests := []struct {
+		name string
+		p    r3.Vec
+		want bool
+	}{
+		{"inside", r3.Vec{X: 0.5, Y: 0.5, Z: 0.5}, true},
+		{"on face", r3.Vec{X: 1, Y: 0.5, Z: 0.5}, true},
+		{"on edge", r3.Vec{X: 0, Y: 1, Z: 0.5}, true},
+		{"on corner", r3.Vec{X: 1, Y: 1, Z: 1}, true},
+		{"just outside", r3.Vec{X: 0.5, Y: 0.5, Z: 1.0001}, false},
+		{"below", r3.Vec{X: 0.5, Y: -0.5, Z: 0.5}, false},
+	}
+
+	for _, tt := range tests {
+		if got := unit.Contains(tt.p); got != tt.want {
+			t.Errorf("%s: Contains(%v) = %v, want %v", tt.name, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestContainsBoxAndIntersects(t *testing.T) {
+	unit := box(r3.Vec{}, r3.Vec{X: 1, Y: 1, Z: 1})
+
+	tests := []struct {
+		name                 string
+		other                *BoundingBox
+		contains, intersects bool
+	}{
+		{"itself", unit, true, true},
+		{"inner", box(r3.Vec{X: 0.25, Y: 0.25, Z: 0.25}, r3.Vec{X: 0.75, Y: 0.75, Z: 0.75}), true, true},
+		{"inner on face", box(r3.Vec{X: 0.5}, r3.Vec{X: 1, Y: 1, Z: 1}), true, true},
+		{"overlapping", box(r3.Vec{X: 0.5, Y: 0.5, Z: 0.5}, r3.Vec{X: 2, Y: 2, Z: 2}), false, true},
+		{"touching at face", box(r3.Vec{X: 1}, r3.Vec{X: 2, Y: 1, Z: 1}), false, true},
+		{"touching at edge", box(r3.Vec{X: 1, Y: 1}, r3.Vec{X: 2, Y: 2, Z: 1}), false, true},
+		{"touching at corner", box(r3.Vec{X: 1, Y: 1, Z: 1}, r3.Vec{X: 2, Y: 2, Z: 2}), false, true},
+		{"disjoint", box(r3.Vec{X: 1.5}, r3.Vec{X: 2, Y: 1, Z: 1}), false, false},
+		{"disjoint on one axis", box(r3.Vec{Z: -2}, r3.Vec{X: 1, Y: 1, Z: -0.5}), false, false},
+	}
+
+	for _, tt := range tests {
+		if got := unit.ContainsBox(tt.other); got != tt.contains {
+			t.Errorf("%s: ContainsBox = %v, want %v", tt.name, got, tt.contains)
+		}
+		if got := unit.Intersects(tt.other); got != tt.intersects {
+			t.Errorf("%s: Intersects = %v, want %v", tt.name, got, tt.intersects)
+		}
+		if got := tt.other.Intersects(unit); got != tt.intersects {
+			t.Errorf("%s: reversed Intersects = %v, want %v", tt.name, got, tt.intersects)
+		}
+	}
+}
+
+func TestDiagonal(t *testing.T) {
+	for _, side := range []float64{1, 2.5} {
+		cube := box(r3.Vec{X: -side / 2, Y: -side / 2, Z: -side / 2}, r3.Vec{X: side / 2, Y: side / 2, Z: side / 2})
+		if got, want := cube.Diagonal(), math.Sqrt(3)*side; math.Abs(got-want) > 1e-6 {
+			t.Errorf("side %v: Diagonal = %v, want %v", side, got, want)
+		}
+	}
+}
+
+func TestLongestAxis(t *testing.T) {
+	tests := []struct {
+		name   string
+		hi     r3.Vec
+		axis   int
+		length float64
+	}{
+		{"x", r3.Vec{X: 3, Y: 1, Z: 2}, 0, 3},
+		{"y", r3.Vec{X: 1, Y: 3, Z: 2}, 1, 3},
+		{"z", r3.Vec{X: 1, Y: 2, Z: 3}, 2, 3},
+		{"cube ties to x", r3.Vec{X: 2, Y: 2, Z: 2}, 0, 2},
+		{"y and z tie to y", r3.Vec{X: 1, Y: 2, Z: 2}, 1, 2},
+	}
+
+	for _, tt := range tests {
+		axis, length := box(r3.Vec{}, tt.hi).LongestAxis()
+		if axis != tt.axis || length != tt.length {
+			t.Errorf("%s: LongestAxis = %d, %v, want %d, %v", tt.name, axis, length, tt.axis, tt.length)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := box(r3.Vec{}, r3.Vec{X: 1, Y: 2, Z: 3})
+	b := box(r3.Vec{}, r3.Vec{X: 1, Y: 2, Z: 3})
+	shifted := box(r3.Vec{}, r3.Vec{X: 1, Y: 2, Z: 3.0001})
+	var none *BoundingBox
+
+	tests := []struct {
+		name        string
+		bb, other   *BoundingBox
+		equal       bool
+		approxEqual bool
+	}{
+		{"same values", a, b, true, true},
+		{"within eps", a, shifted, false, true},
+		{"beyond eps", a, box(r3.Vec{}, r3.Vec{X: 1, Y: 2, Z: 3.01}), false, false},
+		{"nil and nil", none, none, true, true},
+		{"nil and box", none, a, false, false},
+		{"box and nil", a, none, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.bb.Equal(tt.other); got != tt.equal {
+			t.Errorf("%s: Equal = %v, want %v", tt.name, got, tt.equal)
+		}
+		if got := tt.bb.ApproxEqual(tt.other, 1e-3); got != tt.approxEqual {
+			t.Errorf("%s: ApproxEqual = %v, want %v", tt.name, got, tt.approxEqual)
+		}
+	}
+}
+
+func TestApproxEqualAcrossPaths(t *testing.T) {
+	triangles := []Triangle{
+		triangle(r3.Vec{X: 0.1, Y: -1.3, Z: 7.77}, r3.Vec{X: 2.2, Y: 0.3, Z: -4.1}, r3.Vec{X: -3.3, Y: 5.9, Z: 0.7}),
+	}
+
+	// ASCII coordinates are scaled at full precision, binary ones after
+	// rounding to float32, so the boxes can differ in the last bit
+	ascii, err := CalculateBoundingBox(bytes.NewReader(asciiSTL(t, "part", triangles)), WithScale(InchesToMillimeters))
+	if err != nil {
+		t.Fatalf("ascii: %v", err)
+	}
+	binary, err := CalculateBoundingBox(bytes.NewReader(binarySTL(t, triangles)))
+	if err != nil {
+		t.Fatalf("binary: %v", err)
+	}
+	if scaled := ScaleBoundingBox(binary, InchesToMillimeters); !ascii.ApproxEqual(scaled, 1e-4) {
+		t.Errorf("got box %+v, want about %+v", *ascii, *scaled)
+	}
+}
+
+func TestCalculateBoundingBoxF64(t *testing.T) {
+	// Far from the origin, float32 cannot represent the offset exactly
+	offset := r3.Vec{X: 1e7 + 0.125, Y: -3, Z: 0.1}
+	triangles := translate(unitCube(), offset)
+
+	bbox, err := CalculateBoundingBoxF64(bytes.NewReader(asciiSTL(t, "far", triangles)))
+	if err != nil {
+		t.Fatalf("CalculateBoundingBoxF64: %v", err)
+	}
+	wantMax := r3.Add(offset, r3.Vec{X: 1, Y: 1, Z: 1})
+	if bbox.Min != offset || bbox.Max != wantMax {
+		t.Errorf("got min %v max %v, want %v and %v", bbox.Min, bbox.Max, offset, wantMax)
+	}
+	if want := r3.Scale(0.5, r3.Add(offset, wantMax)); bbox.Center != want {
+		t.Errorf("got center %v, want %v", bbox.Center, want)
+	}
+}
+
+func TestScaleBoundingBox(t *testing.T) {
+	bb := box(r3.Vec{X: -1, Y: 2, Z: 0}, r3.Vec{X: 3, Y: 4, Z: 5})
+
+	got := ScaleBoundingBox(bb, -2)
+	if want := box(r3.Vec{X: -6, Y: -8, Z: -10}, r3.Vec{X: 2, Y: -4, Z: 0}); !got.Equal(want) {
+		t.Errorf("negative factor: got %+v, want %+v", *got, *want)
+	}
+
+	empty := newBoundingBox()
+	for _, factor := range []float64{2, -2} {
+		got := ScaleBoundingBox(empty, factor)
+		if !got.IsEmpty() || !got.Equal(empty) {
+			t.Errorf("factor %v: got %+v for an empty box, want it unchanged", factor, *got)
+		}
+		if got == empty {
+			t.Errorf("factor %v: returned the input box instead of a copy", factor)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package stl
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// bvhLeafSize is the maximum number of triangles stored in a BVH leaf
+const bvhLeafSize = 4
+
+// BVH is a bounding volume hierarchy over a set of triangles, accelerating
+// repeated ray queries from O(n) to roughly O(log n) per query
+type BVH struct {
+	triangles []Triangle
+	// order holds triangle indices, arranged so each node covers a contiguous range
+	order []int
+	nodes []bvhNode
+}
+
+// bvhNode is a node of a BVH. Leaves cover order[start:start+count]; interior
+// nodes have a count of 0 and the indices of their children in left and right.
+type bvhNode struct {
+	bounds      BoundingBox
+	left, right int
+	start       int
+	count       int
+}
+
+// NewBVH builds a BVH over the given triangles. Each node is split at the
+// median triangle centroid along the longest axis of its centroid bounds.
+// The triangles are referenced, not copied, and must not be modified while
+// the BVH is in use.
+func NewBVH(triangles []Triangle) *BVH {
+	b := &BVH{
+		triangles: triangles,
+		order:     make([]int, len(triangles)),
+	}
+	for i := range b.order {
+		b.order[i] = i
+	}
+	if len(triangles) > 0 {
+		b.build(0, len(triangles))
+	}
+	return b
+}
+
+// build adds a node covering order[start:end] and its descendants, returning
+// the node's index
+func (b *BVH) build(start, end int) int {
+	index := len(b.nodes)
+	b.nodes = append(b.nodes, bvhNode{left: -1, right: -1})
+
+	bounds := newBoundingBox()
+	centroids := newBoundingBox()
+	for _, i := range b.order[start:end] {
+		updateBoundingBox(bounds, b.triangles[i].Vertices[:])
+		updateBoundingBox(centroids, []r3.Vec{b.triangles[i].Centroid()})
+	}
+	padBoundingBox(bounds)
+
+	axis, extent := centroids.LongestAxis()
+	if end-start <= bvhLeafSize || extent == 0 {
+		b.nodes[index] = bvhNode{bounds: *bounds, left: -1, right: -1, start: start, count: end - start}
+		return index
+	}
+
+	span := b.order[start:end]
+	sort.Slice(span, func(i, j int) bool {
+		return axisValue(b.triangles[span[i]].Centroid(), axis) < axisValue(b.triangles[span[j]].Centroid(), axis)
+	})
+
+	mid := start + (end-start)/2
+	left := b.build(start, mid)
+	right := b.build(mid, end)
+	b.nodes[index] = bvhNode{bounds: *bounds, left: left, right: right}
+	return index
+}
+
+// Intersect returns the nearest triangle hit by the ray from origin along
+// dir, with the same results as RayIntersect over the triangles the BVH was
+// built from
+func (b *BVH) Intersect(origin, dir r3.Vec) (hit bool, t float64, index int) {
+	index = -1
+	if len(b.nodes) == 0 {
+		return false, 0, -1
+	}
+
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		near, ok := rayBoxDistance(&node.bounds, origin, dir)
+		if !ok || (hit && near > t) {
+			continue
+		}
+
+		if node.count == 0 {
+			stack = append(stack, node.left, node.right)
+			continue
+		}
+
+		for _, i := range b.order[node.start : node.start+node.count] {
+			h, ok := intersectTriangle(origin, dir, b.triangles[i].Vertices)
+			if ok && (!hit || h.t < t || (h.t == t && i < index)) {
+				hit, t, index = true, h.t, i
+			}
+		}
+	}
+	return hit, t, index
+}
+
+// rayBoxDistance returns the distance along the ray from origin along dir at
+// which it enters bb (0 if origin is inside), and whether it hits bb at all
+func rayBoxDistance(bb *BoundingBox, origin, dir r3.Vec) (float64, bool) {
+	near, far := 0.0, math.Inf(1)
+
+	lo, hi := bb.MinVec(), bb.MaxVec()
+	for axis := 0; axis < 3; axis++ {
+		o, d := axisValue(origin, axis), axisValue(dir, axis)
+		l, h := axisValue(lo, axis), axisValue(hi, axis)
+
+		if d == 0 {
+			if o < l || o > h {
+				return 0, false
+			}
+			continue
+		}
+
+		t0, t1 := (l-o)/d, (h-o)/d
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		near, far = max(near, t0), min(far, t1)
+		if near > far {
+			return 0, false
+		}
+	}
+	return near, true
+}
+
+// padBoundingBox grows bb by one float32 step on every side, so that vertices
+// rounded toward the center when converted to float32 still lie inside it
+func padBoundingBox(bb *BoundingBox) {
+	down, up := float32(math.Inf(-1)), float32(math.Inf(1))
+	bb.MinX, bb.MinY, bb.MinZ = math.Nextafter32(bb.MinX, down), math.Nextafter32(bb.MinY, down), math.Nextafter32(bb.MinZ, down)
+	bb.MaxX, bb.MaxY, bb.MaxZ = math.Nextafter32(bb.MaxX, up), math.Nextafter32(bb.MaxY, up), math.Nextafter32(bb.MaxZ, up)
+}
+
+// axisValue returns the component of v along axis (0 = X, 1 = Y, 2 = Z)
+func axisValue(v r3.Vec, axis int) float64 {
+	switch axis {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	default:
+		return v.Z
+	}
+}
@@ -0,0 +1,60 @@
+package stl
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestBVHMatchesRayIntersect(t *testing.T) {
+	tests := []struct {
+		name      string
+		triangles []Triangle
+	}{
+		{"cube", unitCube()},
+		{"strip", strip(100)},
+		{"empty", nil},
+	}
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	random := func(bb *BoundingBox, pad float64) r3.Vec {
+		lo, hi := bb.MinVec(), bb.MaxVec()
+		return r3.Vec{
+			X: lo.X - pad + rng.Float64()*(hi.X-lo.X+2*pad),
+			Y: lo.Y - pad + rng.Float64()*(hi.Y-lo.Y+2*pad),
+			Z: lo.Z - pad + rng.Float64()*(hi.Z-lo.Z+2*pad),
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bvh := NewBVH(tt.triangles)
+			bb := BoundingBoxFromTriangles(unitCube())
+			if len(tt.triangles) > 0 {
+				bb = BoundingBoxFromTriangles(tt.triangles)
+			}
+
+			hits := 0
+			for range 1000 {
+				// Aim from around the mesh at a point near it, so rays both
+				// hit and miss
+				origin := random(bb, 2)
+				dir := r3.Sub(random(bb, 0.5), origin)
+
+				hit, dist, index := bvh.Intersect(origin, dir)
+				wantHit, wantDist, wantIndex := RayIntersect(tt.triangles, origin, dir)
+				if hit != wantHit || dist != wantDist || index != wantIndex {
+					t.Fatalf("Intersect(%v, %v) = %v, %v, %d, want %v, %v, %d",
+						origin, dir, hit, dist, index, wantHit, wantDist, wantIndex)
+				}
+				if hit {
+					hits++
+				}
+			}
+			if len(tt.triangles) > 0 && (hits == 0 || hits == 1000) {
+				t.Errorf("%d of 1000 rays hit, want a mix of hits and misses", hits)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// CalculateBoundingBoxFromBytes returns the bounding box of the STL file held
+// in b. It is faster than wrapping b in a bytes.Reader, as binary files are
+// decoded directly from the slice at fixed offsets without copying.
+func CalculateBoundingBoxFromBytes(b []byte, opts ...Option) (*BoundingBox, error) {
+	return calculateBoundingBoxBytes(b, newConfig(opts))
+}
+
+// calculateBoundingBoxBytes computes the bounding box of the STL file held in b
+func calculateBoundingBoxBytes(b []byte, cfg *config) (*BoundingBox, error) {
+	bbox := newBoundingBox()
+
+	err := parseBytes(b, cfg, &visitor{triangle: func(t Triangle) error {
+		updateBoundingBox(bbox, t.Vertices[:])
+		return nil
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	updateCenter(bbox)
+	bbox.Partial = cfg.partial
+	return bbox, nil
+}
+
+// parseBytes detects the format of the STL file held in b and feeds its
+// contents to v. Binary files are decoded directly from the slice.
+func parseBytes(b []byte, cfg *config, v *visitor) error {
+	format := cfg.format
+	if format == FormatUnknown {
+		var err error
+		if format, err = detectedFormat(b[:min(len(b), detectWindow)], int64(len(b))); err != nil {
+			return err
+		}
+	}
+
+	v = cfg.wrap(v)
+
+	if format == FormatASCII {
+		return cfg.limitReached(parseASCII(bytes.NewReader(b), cfg, v))
+	}
+
+	return cfg.limitReached(parseBinaryBytes(b, cfg, v))
+}
+
+// parseBinaryBytes parses a binary STL file held in b without copying it
+func parseBinaryBytes(b []byte, cfg *config, v *visitor) error {
+	if len(b) < 84 {
+		if len(b) < 80 {
+			return readError("error reading header", truncationError(len(b)))
+		}
+		return readError("error reading number of triangles", truncationError(len(b)-80))
+	}
+
+	numTriangles := binary.LittleEndian.Uint32(b[80:84])
+
+	order, numTriangles := cfg.binaryOrder(numTriangles, int64(len(b)))
+	if err := checkBinaryHeader(cfg, numTriangles, int64(len(b))); err != nil {
+		return err
+	}
+
+	v.beginSolid("", int(numTriangles))
+
+	accepted := 0
+	for i := 0; i < int(numTriangles); i++ {
+		if i%checkInterval == 0 {
+			if err := cfg.ctx.Err(); err != nil {
+				return err
+			}
+			cfg.reportProgress(i, int(numTriangles))
+		}
+
+		offset := binaryFileSize(uint32(i))
+		remaining := int64(len(b)) - offset
+		if remaining < 48 {
+			return readError(fmt.Sprintf("error reading triangle %d", i), truncationError(int(max(remaining, 0))))
+		}
+		if remaining < 50 {
+			return readError("error reading attribute byte count", truncationError(int(remaining-48)))
+		}
+
+		record := b[offset : offset+50]
+		if v.attribute != nil {
+			v.attribute(order.Uint16(record[48:50]))
+		}
+		ok, err := visitTriangle(v, decodeBinaryTriangle(record, order))
+		if err == errTriangleLimit && i == int(numTriangles)-1 {
+			// The limit fell on the last declared triangle, so the file was
+			// read in full
+			ok, err = true, nil
+		}
+		if err != nil {
+			return err
+		}
+		if ok {
+			accepted++
+		}
+	}
+	if accepted == 0 {
+		return allSkipped(int(numTriangles))
+	}
+
+	cfg.reportProgress(int(numTriangles), int(numTriangles))
+	return nil
+}
+
+// decodeBinaryTriangle decodes the normal and vertices of a 50-byte binary STL
+// record in the given byte order
+func decodeBinaryTriangle(record []byte, order binary.ByteOrder) Triangle {
+	var t Triangle
+	t.Normal = decodeBinaryVec(record[0:12], order)
+	for j := range t.Vertices {
+		t.Vertices[j] = decodeBinaryVec(record[12+12*j:24+12*j], order)
+	}
+	return t
+}
+
+// decodeBinaryVec decodes three float32 values into an r3.Vec
+func decodeBinaryVec(b []byte, order binary.ByteOrder) r3.Vec {
+	return r3.Vec{
+		X: float64(math.Float32frombits(order.Uint32(b[0:4]))),
+		Y: float64(math.Float32frombits(order.Uint32(b[4:8]))),
+		Z: float64(math.Float32frombits(order.Uint32(b[8:12]))),
+	}
+}
+
+// truncationError returns the error a stream read would report after
+// reading n bytes of a record that was cut short
+func truncationError(n int) error {
+	if n == 0 {
+		return io.EOF
+	}
+	return io.ErrUnexpectedEOF
+}
@@ -0,0 +1,117 @@
+package stl
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Cache memoizes bounding boxes by the SHA-256 hash of file contents, so
+// re-measuring an unchanged file only costs a hash. It is safe for concurrent
+// use. A bounded cache evicts the least recently used entry when full.
+type Cache struct {
+	mu         sync.RWMutex
+	maxEntries int
+	opts       []Option
+	// entries maps content hashes to elements of order, whose values are *cacheEntry
+	entries map[[sha256.Size]byte]*list.Element
+	// order lists entries from most to least recently used
+	order *list.List
+}
+
+// cacheEntry is a cached bounding box and the hash it is stored under
+type cacheEntry struct {
+	key  [sha256.Size]byte
+	bbox BoundingBox
+}
+
+// NewCache returns an empty cache holding at most maxEntries bounding boxes,
+// or any number if maxEntries is 0 or less. opts are applied whenever a box is
+// computed; since they are not part of the key, use separate caches for
+// different options.
+func NewCache(maxEntries int, opts ...Option) *Cache {
+	return &Cache{
+		maxEntries: maxEntries,
+		opts:       opts,
+		entries:    make(map[[sha256.Size]byte]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// CachedBoundingBox returns the bounding box of the STL file at path, computing
+// it only if no file with the same contents has been measured before. The
+// file is hashed and, on a miss, parsed in a second pass, so memory use does
+// not grow with file size. Concurrent misses for the same contents may each
+// compute the box. The returned box is a copy that callers may modify.
+func (c *Cache) CachedBoundingBox(path string) (*BoundingBox, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	var key [sha256.Size]byte
+	hash.Sum(key[:0])
+
+	if bbox, ok := c.get(key); ok {
+		return bbox, nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error seeking file: %w", err)
+	}
+	bbox, err := CalculateBoundingBox(file, c.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.put(key, bbox)
+	return bbox, nil
+}
+
+// Len returns the number of cached bounding boxes
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// get returns a copy of the box cached under key, marking it recently used
+func (c *Cache) get(key [sha256.Size]byte) (*BoundingBox, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	bbox := element.Value.(*cacheEntry).bbox
+	return &bbox, true
+}
+
+// put caches a copy of bbox under key, evicting the least recently used entry
+// if the cache is full
+func (c *Cache) put(key [sha256.Size]byte, bbox *BoundingBox) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, bbox: *bbox})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	stl "github.com/nfranczak/stl-bounding-box"
+)
+
+// batchResult is one NDJSON line of batch output
+type batchResult struct {
+	File        string           `json:"file"`
+	BoundingBox *stl.BoundingBox `json:"bounding_box,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// writeNDJSON returns a batch writer that encodes each result as one JSON line
+func writeNDJSON(w io.Writer) func(batchResult) error {
+	encoder := json.NewEncoder(w)
+	return func(result batchResult) error {
+		return encoder.Encode(result)
+	}
+}
+
+// writeCSV returns a batch writer that writes each successful result as a CSV
+// row with the given number of decimal places after a header row. Failed files
+// are reported to errOut instead, as the CSV columns have no room for an error.
+func writeCSV(w, errOut io.Writer, precision int) func(batchResult) error {
+	wroteHeader := false
+	return func(result batchResult) error {
+		if !wroteHeader {
+			if err := stl.WriteCSVHeader(w); err != nil {
+				return err
+			}
+			wroteHeader = true
+		}
+		if result.Error != "" {
+			_, err := fmt.Fprintf(errOut, "Error: %s: %s\n", result.File, result.Error)
+			return err
+		}
+		return stl.WriteCSVRowWithPrecision(w, result.File, result.BoundingBox, precision)
+	}
+}
+
+// runBatch walks dir for *.stl files, computes their bounding boxes concurrently,
+// and passes each result to write, one per file. Errors on individual files are
+// reported in their result and do not stop the run. It returns the number of
+// files that failed. opts are applied to every file.
+func runBatch(dir string, write func(batchResult) error, opts ...stl.Option) (int, error) {
+	paths := make(chan string)
+	results := make(chan batchResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				result := batchResult{File: path}
+				bbox, err := stl.CalculateBoundingBoxFromFile(path, opts...)
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.BoundingBox = bbox
+				}
+				results <- result
+			}
+		}()
+	}
+
+	walkErr := make(chan error, 1)
+	go func() {
+		walkErr <- filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// Report unreadable entries without aborting the walk
+				results <- batchResult{File: path, Error: err.Error()}
+				return nil
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".stl") {
+				paths <- path
+			}
+			return nil
+		})
+		close(paths)
+		wg.Wait()
+		close(results)
+	}()
+
+	failed := 0
+	var writeErr error
+	for result := range results {
+		if result.Error != "" {
+			failed++
+		}
+		if writeErr == nil {
+			writeErr = write(result)
+		}
+	}
+
+	if err := <-walkErr; err != nil {
+		return failed, err
+	}
+	return failed, writeErr
+}
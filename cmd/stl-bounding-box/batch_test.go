@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	stl "github.com/nfranczak/stl-bounding-box"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestRunBatchNDJSON(t *testing.T) {
+	small := []stl.Triangle{{Vertices: [3]r3.Vec{{X: 0}, {X: 1}, {Y: 1}}}}
+	large := []stl.Triangle{{Vertices: [3]r3.Vec{{X: -2, Z: 1}, {X: 3, Y: 4}, {Y: 5, Z: 6}}}}
+
+	var ascii, binary bytes.Buffer
+	if err := stl.WriteASCII(&ascii, "part", small); err != nil {
+		t.Fatalf("WriteASCII: %v", err)
+	}
+	if err := stl.WriteBinary(&binary, large); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"small.stl":        ascii.Bytes(),
+		"nested/large.STL": binary.Bytes(),
+		"bad.stl":          []byte("solid broken\nfacet normal x\n"),
+		"notes.txt":        []byte("not an STL file"),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	failed, err := runBatch(dir, writeNDJSON(&out))
+	if err != nil {
+		t.Fatalf("runBatch: %v", err)
+	}
+	if failed != 1 {
+		t.Errorf("got %d failed files, want 1", failed)
+	}
+
+	type vec struct{ X, Y, Z float32 }
+	want := map[string]*stl.BoundingBox{
+		"small.stl":        stl.BoundingBoxFromTriangles(small),
+		"nested/large.STL": stl.BoundingBoxFromTriangles(large),
+		"bad.stl":          nil,
+	}
+
+	// Results arrive in completion order, one JSON object per line
+	scanner := bufio.NewScanner(&out)
+	seen := 0
+	for scanner.Scan() {
+		var line struct {
+			File        string
+			BoundingBox *struct{ Min, Max vec } `json:"bounding_box"`
+			Error       string
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		name, err := filepath.Rel(dir, line.File)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bbox, ok := want[filepath.ToSlash(name)]
+		if !ok {
+			t.Errorf("unexpected result for %s", name)
+			continue
+		}
+		seen++
+
+		if bbox == nil {
+			if line.Error == "" || line.BoundingBox != nil {
+				t.Errorf("%s: got %s, want only an error", name, scanner.Text())
+			}
+			continue
+		}
+		if line.Error != "" || line.BoundingBox == nil {
+			t.Errorf("%s: got %s, want only a bounding box", name, scanner.Text())
+			continue
+		}
+		wantMin, wantMax := vec{bbox.MinX, bbox.MinY, bbox.MinZ}, vec{bbox.MaxX, bbox.MaxY, bbox.MaxZ}
+		if line.BoundingBox.Min != wantMin || line.BoundingBox.Max != wantMax {
+			t.Errorf("%s: got min %v max %v, want min %v max %v", name, line.BoundingBox.Min, line.BoundingBox.Max, wantMin, wantMax)
+		}
+	}
+	if seen != len(want) {
+		t.Errorf("got %d results, want %d", seen, len(want))
+	}
+}
@@ -1,32 +1,161 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	stl "github.com/nfranczak/stl-bounding-box"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: stl-bounding-box <file.stl>")
+	jsonOutput := flag.Bool("json", false, "print the bounding box as JSON")
+	csvOutput := flag.Bool("csv", false, "print bounding boxes as CSV")
+	formatName := flag.String("format", "auto", "STL format: ascii, binary, or auto to detect")
+	centerOnly := flag.Bool("center", false, "print only the center")
+	dimensionsOnly := flag.Bool("dimensions", false, "print only the dimensions")
+	volumeOnly := flag.Bool("volume", false, "print only the volume")
+	showStats := flag.Bool("stats", false, "also print mesh statistics")
+	precision := flag.Int("precision", 5, "number of decimal places in printed values")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: stl-bounding-box [flags] <file.stl | directory | ->")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
 		os.Exit(1)
 	}
 
-	filePath := os.Args[1]
+	if *precision < 0 {
+		fmt.Fprintf(os.Stderr, "Error: precision must not be negative, got %d\n", *precision)
+		os.Exit(1)
+	}
 
-	bbox, err := stl.CalculateBoundingBoxFromFile(filePath)
+	// Statistics are only printed in the full text report
+	if *showStats && (*jsonOutput || *csvOutput || *centerOnly || *dimensionsOnly || *volumeOnly) {
+		fmt.Fprintln(os.Stderr, "Error: --stats cannot be combined with --json, --csv, --center, --dimensions or --volume")
+		os.Exit(1)
+	}
+
+	format, err := stl.ParseFormatString(*formatName)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	opts := []stl.Option{stl.WithFormat(format)}
+
+	filePath := flag.Arg(0)
+
+	// A directory is processed in batch mode, one NDJSON line per STL file
+	if info, err := os.Stat(filePath); err == nil && info.IsDir() {
+		if *showStats {
+			fmt.Fprintln(os.Stderr, "Error: --stats is not supported for directories")
+			os.Exit(1)
+		}
+		write := writeNDJSON(os.Stdout)
+		if *csvOutput {
+			write = writeCSV(os.Stdout, os.Stderr, *precision)
+		}
+		failed, err := runBatch(filePath, write, opts...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	var bbox *stl.BoundingBox
+	var stats *stl.Stats
+	if *showStats {
+		bbox, stats, err = calculateStats(filePath, os.Stdin, opts...)
+	} else {
+		bbox, err = calculateBoundingBox(filePath, os.Stdin, opts...)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *csvOutput {
+		if err := stl.WriteCSVHeader(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := stl.WriteCSVRowWithPrecision(os.Stdout, filePath, bbox, *precision); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *jsonOutput {
+		out, err := json.Marshal(bbox)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		return
+	}
 
 	width, height, depth := bbox.Dimensions()
 
-	fmt.Printf("Bounding Box:\n")
-	fmt.Printf("  Min: (%.5f, %.5f, %.5f)\n", bbox.MinX, bbox.MinY, bbox.MinZ)
-	fmt.Printf("  Max: (%.5f, %.5f, %.5f)\n", bbox.MaxX, bbox.MaxY, bbox.MaxZ)
-	fmt.Printf("  Dimensions: (%.5f, %.5f, %.5f)\n", width, height, depth)
-	fmt.Printf("  Center: (%.5f, %.5f, %.5f)\n", bbox.Center.X, bbox.Center.Y, bbox.Center.Z)
-	fmt.Printf("  Volume: %.5f\n", bbox.Volume())
+	p := *precision
+
+	// Selected values are printed alone, one per line in a fixed order, so
+	// scripts can capture them without parsing the full report
+	if *centerOnly || *dimensionsOnly || *volumeOnly {
+		if *centerOnly {
+			fmt.Printf("%.*f %.*f %.*f\n", p, bbox.Center.X, p, bbox.Center.Y, p, bbox.Center.Z)
+		}
+		if *dimensionsOnly {
+			fmt.Printf("%.*f %.*f %.*f\n", p, width, p, height, p, depth)
+		}
+		if *volumeOnly {
+			fmt.Printf("%.*f\n", p, bbox.Volume())
+		}
+		return
+	}
+
+	fmt.Printf("%.*v\n", p, bbox)
+
+	if stats != nil {
+		fmt.Printf("Statistics:\n")
+		fmt.Printf("  Triangles: %d\n", stats.TriangleCount)
+		fmt.Printf("  Surface Area: %.*f\n", p, stats.SurfaceArea)
+		fmt.Printf("  Mesh Volume: %.*f\n", p, stats.Volume)
+		fmt.Printf("  Degenerate Triangles: %d\n", stats.DegenerateCount)
+		fmt.Printf("  Watertight: %t\n", stats.Watertight)
+	}
+}
+
+// calculateBoundingBox returns the bounding box of the STL file at path, or of
+// stdin if path is "-". stdin need not be seekable.
+func calculateBoundingBox(path string, stdin io.Reader, opts ...stl.Option) (*stl.BoundingBox, error) {
+	if path == "-" {
+		return stl.CalculateBoundingBox(stdin, opts...)
+	}
+	return stl.CalculateBoundingBoxFromFile(path, opts...)
+}
+
+// calculateStats returns the bounding box and statistics of the STL file at
+// path, or of stdin if path is "-", reading it once
+func calculateStats(path string, stdin io.Reader, opts ...stl.Option) (*stl.BoundingBox, *stl.Stats, error) {
+	if path == "-" {
+		return stl.CalculateStats(stdin, opts...)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+	return stl.CalculateStats(file, opts...)
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	stl "github.com/nfranczak/stl-bounding-box"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// pipe hides every method of the wrapped reader but Read, as stdin does when
+// it is a pipe
+type pipe struct {
+	r io.Reader
+}
+
+func (p pipe) Read(b []byte) (int, error) {
+	return p.r.Read(b)
+}
+
+func TestCalculateBoundingBoxStdin(t *testing.T) {
+	triangles := []stl.Triangle{
+		{Vertices: [3]r3.Vec{{X: -1, Y: 0, Z: 2}, {X: 3, Y: 1, Z: 2}, {X: 0, Y: 4, Z: -5}}},
+		{Vertices: [3]r3.Vec{{X: 0, Y: 0, Z: 0}, {X: 1, Y: 0, Z: 0}, {X: 0, Y: 1, Z: 7}}},
+	}
+	want := stl.BoundingBoxFromTriangles(triangles)
+
+	var ascii, binary bytes.Buffer
+	if err := stl.WriteASCII(&ascii, "part", triangles); err != nil {
+		t.Fatalf("WriteASCII: %v", err)
+	}
+	if err := stl.WriteBinary(&binary, triangles); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+
+	for name, data := range map[string][]byte{"ascii": ascii.Bytes(), "binary": binary.Bytes()} {
+		t.Run(name, func(t *testing.T) {
+			stdin := pipe{bytes.NewReader(data)}
+			if _, ok := io.Reader(stdin).(io.Seeker); ok {
+				t.Fatal("stdin stand-in is seekable")
+			}
+
+			bbox, err := calculateBoundingBox("-", stdin)
+			if err != nil {
+				t.Fatalf("calculateBoundingBox: %v", err)
+			}
+			if !bbox.Equal(want) {
+				t.Errorf("got box %+v, want %+v", *bbox, *want)
+			}
+
+			bbox, stats, err := calculateStats("-", pipe{bytes.NewReader(data)})
+			if err != nil {
+				t.Fatalf("calculateStats: %v", err)
+			}
+			if !bbox.Equal(want) {
+				t.Errorf("calculateStats: got box %+v, want %+v", *bbox, *want)
+			}
+			if stats.TriangleCount != len(triangles) {
+				t.Errorf("calculateStats: got %d triangles, want %d", stats.TriangleCount, len(triangles))
+			}
+		})
+	}
+}
+
+func TestWriteCSVPrecision(t *testing.T) {
+	bbox := stl.BoundingBoxFromTriangles([]stl.Triangle{
+		{Vertices: [3]r3.Vec{{X: 0.125}, {X: 1, Y: 2}, {Z: 3}}},
+	})
+
+	var out, errOut bytes.Buffer
+	write := writeCSV(&out, &errOut, 2)
+	if err := write(batchResult{File: "part.stl", BoundingBox: bbox}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	want := "file,min_x,min_y,min_z,max_x,max_y,max_z,width,height,depth,volume\n" +
+		"part.stl,0.00,0.00,0.00,1.00,2.00,3.00,1.00,2.00,3.00,6.00\n"
+	if out.String() != want {
+		t.Errorf("got CSV\n%s\nwant\n%s", out.String(), want)
+	}
+}
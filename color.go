@@ -0,0 +1,96 @@
+package stl
+
+import (
+	"io"
+)
+
+// Color is a per-facet color decoded from a binary STL attribute field
+type Color struct {
+	R, G, B uint8
+	// Valid reports whether the facet carries its own color
+	Valid bool
+}
+
+// ColorConvention selects how binary STL attribute bytes are decoded into a Color
+type ColorConvention int
+
+const (
+	// ColorNone leaves colors undecoded; only the raw attribute is returned
+	ColorNone ColorConvention = iota
+	// ColorMaterialise decodes the Materialise Magics layout: red in bits 0-4,
+	// green in bits 5-9, blue in bits 10-14. Bit 15 is clear when the facet
+	// has its own color.
+	ColorMaterialise
+	// ColorRaw555 decodes the VisCAM/SolidView 5-5-5 layout: blue in bits 0-4,
+	// green in bits 5-9, red in bits 10-14. Bit 15 is set when the color is valid.
+	ColorRaw555
+)
+
+// AttributedTriangle is a Triangle together with its binary STL attribute data
+type AttributedTriangle struct {
+	Triangle
+	// Attribute is the raw 2-byte attribute field; always 0 for ASCII files
+	Attribute uint16
+	// Color is decoded from Attribute according to the requested convention
+	Color Color
+}
+
+// ParseSTLWithAttributes reads an STL file from the given io.Reader and returns
+// all of its triangles along with their attribute fields, decoding per-facet
+// colors using the given convention. ASCII files have no attributes, so every
+// triangle has a zero Attribute and an invalid Color.
+func ParseSTLWithAttributes(r io.Reader, convention ColorConvention, opts ...Option) ([]AttributedTriangle, error) {
+	var triangles []AttributedTriangle
+	var attribute uint16
+	// binary is set once an attribute field has been read, as ASCII files
+	// have none to decode
+	binary := false
+
+	err := parse(r, newConfig(opts), &visitor{
+		solid: func(_ string, count int) {
+			if count > 0 && triangles == nil {
+				triangles = make([]AttributedTriangle, 0, preallocCount(count))
+			}
+		},
+		attribute: func(a uint16) {
+			attribute = a
+			binary = true
+		},
+		triangle: func(t Triangle) error {
+			triangle := AttributedTriangle{Triangle: t, Attribute: attribute}
+			if binary {
+				triangle.Color = decodeColor(attribute, convention)
+			}
+			triangles = append(triangles, triangle)
+			attribute = 0
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return triangles, nil
+}
+
+// decodeColor decodes a binary STL attribute field into a Color
+func decodeColor(attribute uint16, convention ColorConvention) Color {
+	low := uint8(attribute & 0x1f)
+	mid := uint8((attribute >> 5) & 0x1f)
+	high := uint8((attribute >> 10) & 0x1f)
+	flag := attribute&0x8000 != 0
+
+	switch convention {
+	case ColorMaterialise:
+		return Color{R: expand5(low), G: expand5(mid), B: expand5(high), Valid: !flag}
+	case ColorRaw555:
+		return Color{R: expand5(high), G: expand5(mid), B: expand5(low), Valid: flag}
+	default:
+		return Color{}
+	}
+}
+
+// expand5 scales a 5-bit color channel to 8 bits
+func expand5(v uint8) uint8 {
+	return v<<3 | v>>2
+}
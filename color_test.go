@@ -0,0 +1,60 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestDecodeColor(t *testing.T) {
+	tests := []struct {
+		name       string
+		attribute  uint16
+		convention ColorConvention
+		want       Color
+	}{
+		{"materialise red", 0x001f, ColorMaterialise, Color{R: 255, Valid: true}},
+		{"materialise blue", 0x7c00, ColorMaterialise, Color{B: 255, Valid: true}},
+		{"materialise mixed", 0x0210, ColorMaterialise, Color{R: 132, G: 132, Valid: true}},
+		{"materialise no color", 0x801f, ColorMaterialise, Color{R: 255}},
+		{"raw555 blue", 0x801f, ColorRaw555, Color{B: 255, Valid: true}},
+		{"raw555 red", 0xfc00, ColorRaw555, Color{R: 255, Valid: true}},
+		{"raw555 no color", 0x001f, ColorRaw555, Color{B: 255}},
+		{"none", 0x801f, ColorNone, Color{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeColor(tt.attribute, tt.convention); got != tt.want {
+				t.Errorf("decodeColor(%#04x) = %+v, want %+v", tt.attribute, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSTLWithAttributes(t *testing.T) {
+	triangles := []Triangle{triangle(r3.Vec{}, r3.Vec{X: 1}, r3.Vec{Y: 1})}
+
+	data := binarySTL(t, triangles)
+	binary.LittleEndian.PutUint16(data[84+48:], 0x001f)
+
+	for _, convention := range []ColorConvention{ColorMaterialise, ColorRaw555} {
+		got, err := ParseSTLWithAttributes(bytes.NewReader(asciiSTL(t, "part", triangles)), convention)
+		if err != nil {
+			t.Fatalf("ASCII: %v", err)
+		}
+		if got[0].Attribute != 0 || got[0].Color != (Color{}) {
+			t.Errorf("ASCII with convention %d: got attribute %#04x and color %+v, want zero values", convention, got[0].Attribute, got[0].Color)
+		}
+
+		got, err = ParseSTLWithAttributes(bytes.NewReader(data), convention)
+		if err != nil {
+			t.Fatalf("binary: %v", err)
+		}
+		if want := decodeColor(0x001f, convention); got[0].Attribute != 0x001f || got[0].Color != want {
+			t.Errorf("binary with convention %d: got attribute %#04x and color %+v, want 0x001f and %+v", convention, got[0].Attribute, got[0].Color, want)
+		}
+	}
+}
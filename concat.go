@@ -0,0 +1,95 @@
+package stl
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// ParseConcatenated reads a stream of STL files written back to back, such as
+// a data feed of binary files, and returns the bounding box of each in order.
+// Each binary file is read exactly up to its last declared triangle before
+// the next is parsed. ASCII content has no length prefix, so it is parsed to
+// the end of the input as a single file; concatenated ASCII solids therefore
+// yield one box. opts apply to each file separately.
+func ParseConcatenated(r io.Reader, opts ...Option) ([]*BoundingBox, error) {
+	br := bufio.NewReaderSize(r, detectWindow)
+
+	var boxes []*BoundingBox
+	for {
+		data, err := br.Peek(detectWindow)
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("error reading header: %w", err)
+		}
+		if len(data) == 0 && len(boxes) > 0 {
+			return boxes, nil
+		}
+
+		cfg := newConfig(opts)
+		format := cfg.format
+		if format == FormatUnknown {
+			if format, err = detectedFormat(ownBytes(data), -1); err != nil {
+				return nil, fmt.Errorf("error parsing STL file %d: %w", len(boxes), err)
+			}
+		}
+
+		bbox := newBoundingBox()
+		v := cfg.wrap(&visitor{triangle: func(t Triangle) error {
+			updateBoundingBox(bbox, t.Vertices[:])
+			return nil
+		}})
+		if format == FormatASCII {
+			err = cfg.limitReached(parseASCII(br, cfg, v))
+		} else {
+			err = parseBinarySegment(br, data, cfg, v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing STL file %d: %w", len(boxes), err)
+		}
+
+		updateCenter(bbox)
+		bbox.Partial = cfg.partial
+		boxes = append(boxes, bbox)
+
+		if format == FormatASCII {
+			return boxes, nil
+		}
+	}
+}
+
+// ownBytes returns the prefix of data that can belong to the file starting it:
+// all of it, unless it is long enough to be a binary file declaring fewer
+// bytes. This keeps the keywords of a following ASCII file from making a
+// binary file whose header starts with "solid" look like ASCII.
+func ownBytes(data []byte) []byte {
+	if len(data) >= 84 {
+		if size := binaryFileSize(binary.LittleEndian.Uint32(data[80:84])); size < int64(len(data)) {
+			return data[:size]
+		}
+	}
+	return data
+}
+
+// parseBinarySegment parses one binary STL file from br, whose leading bytes
+// are data, leaving br at the start of the next file even if parsing stops
+// early at the triangle limit
+func parseBinarySegment(br *bufio.Reader, data []byte, cfg *config, v *visitor) error {
+	size := int64(-1)
+	if len(data) >= 84 {
+		_, count := cfg.binaryOrder(binary.LittleEndian.Uint32(data[80:84]), -1)
+		size = binaryFileSize(count)
+	}
+	if size < 0 {
+		return parseBinary(br, cfg, -1, v)
+	}
+
+	segment := &io.LimitedReader{R: br, N: size}
+	if err := cfg.limitReached(parseBinary(segment, cfg, -1, v)); err != nil {
+		return err
+	}
+	if _, err := io.Copy(io.Discard, segment); err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+	return nil
+}
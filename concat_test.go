@@ -0,0 +1,63 @@
+package stl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestParseConcatenated(t *testing.T) {
+	cube := unitCube()
+	shifted := translate(cube, r3.Vec{X: 5, Y: 5, Z: 5})
+
+	tests := []struct {
+		name  string
+		parts [][]byte
+		want  []*BoundingBox
+	}{
+		{
+			"binary then ascii",
+			[][]byte{binarySTL(t, cube), asciiSTL(t, "b", shifted)},
+			[]*BoundingBox{BoundingBoxFromTriangles(cube), BoundingBoxFromTriangles(shifted)},
+		},
+		{
+			// ASCII has no length prefix, so it is parsed to the end of the
+			// input and the binary file after endsolid is trailing junk
+			"ascii then binary",
+			[][]byte{asciiSTL(t, "a", cube), binarySTL(t, shifted)},
+			[]*BoundingBox{BoundingBoxFromTriangles(cube)},
+		},
+		{
+			"two binary",
+			[][]byte{binarySTL(t, cube), binarySTL(t, shifted)},
+			[]*BoundingBox{BoundingBoxFromTriangles(cube), BoundingBoxFromTriangles(shifted)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes, err := ParseConcatenated(bytes.NewReader(bytes.Join(tt.parts, nil)))
+			if err != nil {
+				t.Fatalf("ParseConcatenated: %v", err)
+			}
+			if len(boxes) != len(tt.want) {
+				t.Fatalf("got %d boxes, want %d", len(boxes), len(tt.want))
+			}
+			for i := range boxes {
+				if !boxes[i].Equal(tt.want[i]) {
+					t.Errorf("box %d = %v, want %v", i, boxes[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("truncated second file", func(t *testing.T) {
+		second := binarySTL(t, shifted)
+		data := append(binarySTL(t, cube), second[:len(second)-10]...)
+		if _, err := ParseConcatenated(bytes.NewReader(data)); !errors.Is(err, ErrTruncated) {
+			t.Errorf("got error %v, want ErrTruncated", err)
+		}
+	})
+}
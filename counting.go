@@ -0,0 +1,40 @@
+package stl
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// CountingTriangleReader streams the triangles of an STL file like
+// ForEachTriangle while counting them, so other goroutines can poll progress
+// with Count without the parser needing a callback
+type CountingTriangleReader struct {
+	r     io.Reader
+	opts  []Option
+	count atomic.Int64
+}
+
+// NewCountingTriangleReader returns a reader streaming the STL file in r,
+// parsed according to opts
+func NewCountingTriangleReader(r io.Reader, opts ...Option) *CountingTriangleReader {
+	return &CountingTriangleReader{r: r, opts: opts}
+}
+
+// ForEach parses the file, calling fn for each triangle in file order and
+// counting each one fn accepts. It behaves like ForEachTriangle and must be
+// called only once.
+func (c *CountingTriangleReader) ForEach(fn func(Triangle) error) error {
+	return ForEachTriangle(c.r, func(t Triangle) error {
+		if err := fn(t); err != nil {
+			return err
+		}
+		c.count.Add(1)
+		return nil
+	}, c.opts...)
+}
+
+// Count returns the number of triangles processed so far. It is safe to call
+// concurrently with ForEach.
+func (c *CountingTriangleReader) Count() int {
+	return int(c.count.Load())
+}
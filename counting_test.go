@@ -0,0 +1,39 @@
+package stl
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestCountingTriangleReaderConcurrent polls Count while ForEach runs; run it
+// with -race to check the two are safe to use together
+func TestCountingTriangleReaderConcurrent(t *testing.T) {
+	const n = 20000
+	reader := NewCountingTriangleReader(bytes.NewReader(binarySTL(t, strip(n))))
+
+	done := make(chan error)
+	go func() {
+		done <- reader.ForEach(func(Triangle) error { return nil })
+	}()
+
+	last := 0
+	for polling := true; polling; {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("ForEach: %v", err)
+			}
+			polling = false
+		default:
+		}
+
+		count := reader.Count()
+		if count < last || count > n {
+			t.Fatalf("Count = %d after %d, want a non-decreasing count up to %d", count, last, n)
+		}
+		last = count
+	}
+	if last != n {
+		t.Errorf("final Count = %d, want %d", last, n)
+	}
+}
@@ -0,0 +1,58 @@
+package stl
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// csvHeader lists the columns written by WriteCSVRow
+var csvHeader = []string{
+	"file",
+	"min_x", "min_y", "min_z",
+	"max_x", "max_y", "max_z",
+	"width", "height", "depth",
+	"volume",
+}
+
+// WriteCSVHeader writes the CSV header row matching WriteCSVRow to w
+func WriteCSVHeader(w io.Writer) error {
+	return writeCSVRecord(w, csvHeader)
+}
+
+// WriteCSVRow writes one CSV row describing the bounding box of file to w.
+// Coordinates, dimensions, and volume are formatted with 5 decimal places,
+// matching String and the command's default --precision.
+func WriteCSVRow(w io.Writer, file string, bb *BoundingBox) error {
+	return WriteCSVRowWithPrecision(w, file, bb, defaultPrecision)
+}
+
+// WriteCSVRowWithPrecision writes one CSV row describing the bounding box of
+// file to w, formatting coordinates, dimensions, and volume with the given
+// number of decimal places.
+func WriteCSVRowWithPrecision(w io.Writer, file string, bb *BoundingBox, precision int) error {
+	width, height, depth := bb.Dimensions()
+	values := []float32{
+		bb.MinX, bb.MinY, bb.MinZ,
+		bb.MaxX, bb.MaxY, bb.MaxZ,
+		width, height, depth,
+		bb.Volume(),
+	}
+
+	record := make([]string, 0, len(csvHeader))
+	record = append(record, file)
+	for _, v := range values {
+		record = append(record, strconv.FormatFloat(float64(v), 'f', precision, 32))
+	}
+	return writeCSVRecord(w, record)
+}
+
+// writeCSVRecord writes a single CSV record to w
+func writeCSVRecord(w io.Writer, record []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,32 @@
+package stl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Sentinel errors returned (wrapped) by the parsing functions.
+// Use errors.Is to test for them.
+var (
+	// ErrNotSTL indicates the input is not recognizable as an STL file
+	ErrNotSTL = errors.New("not an STL file")
+	// ErrTruncated indicates the input ended before the data it declares
+	ErrTruncated = errors.New("truncated STL file")
+	// ErrEmptyMesh indicates the input or mesh contains no triangles
+	ErrEmptyMesh = errors.New("empty mesh")
+	// ErrInvalidVertex indicates an ASCII facet with a malformed normal or
+	// vertex, or with the wrong number of vertices
+	ErrInvalidVertex = errors.New("invalid vertex")
+	// ErrNonFiniteVertex indicates a vertex with a NaN or infinite coordinate
+	ErrNonFiniteVertex = errors.New("non-finite vertex")
+)
+
+// readError wraps an error from reading part of a binary STL file,
+// marking short reads with ErrTruncated
+func readError(what string, err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %s: %w", ErrTruncated, what, err)
+	}
+	return fmt.Errorf("%s: %w", what, err)
+}
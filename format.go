@@ -0,0 +1,127 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format identifies the encoding of an STL file
+type Format int
+
+const (
+	// FormatUnknown means the format has not been determined.
+	// When passed to WithFormat, the format is detected automatically.
+	FormatUnknown Format = iota
+	// FormatASCII is the text-based STL format
+	FormatASCII
+	// FormatBinary is the binary STL format
+	FormatBinary
+)
+
+// String returns "ascii", "binary", or "unknown"
+func (f Format) String() string {
+	switch f {
+	case FormatASCII:
+		return "ascii"
+	case FormatBinary:
+		return "binary"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFormatString returns the Format named by s, as returned by
+// Format.String. Matching is case-insensitive, and "auto" is accepted as an
+// alias for "unknown", which requests automatic detection.
+func ParseFormatString(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "ascii":
+		return FormatASCII, nil
+	case "binary":
+		return FormatBinary, nil
+	case "unknown", "auto":
+		return FormatUnknown, nil
+	default:
+		return FormatUnknown, fmt.Errorf("invalid format %q: must be one of ascii, binary, auto, unknown", s)
+	}
+}
+
+// utf8BOM is the byte order mark some Windows tools write at the start of text files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// detectWindow is how many leading bytes are inspected to detect the format
+const detectWindow = 1024
+
+// DetectFormat inspects the start of an STL file and returns its format
+// without parsing it. Because some binary files begin their header with
+// "solid", the "solid" prefix alone is not trusted: for seekable readers the
+// file size is checked against the declared triangle count, and the leading
+// bytes are scanned for ASCII keywords. FormatUnknown is returned for input
+// that is neither plausibly ASCII nor binary.
+//
+// Seekable readers are restored to their original position; other readers
+// have up to 1024 bytes consumed.
+func DetectFormat(r io.Reader) (Format, error) {
+	size, err := remainingSize(r)
+	if err != nil {
+		return FormatUnknown, err
+	}
+
+	if seeker, ok := r.(io.Seeker); ok && size >= 0 {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return FormatUnknown, fmt.Errorf("error seeking file: %w", err)
+		}
+		defer seeker.Seek(start, io.SeekStart)
+	}
+
+	window := make([]byte, detectWindow)
+	n, err := io.ReadFull(r, window)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return FormatUnknown, fmt.Errorf("error reading header: %w", err)
+	}
+
+	return sniffFormat(window[:n], size), nil
+}
+
+// sniffFormat classifies an STL file from its leading bytes and, if known
+// (non-negative), its total size
+func sniffFormat(data []byte, size int64) Format {
+	if len(data) == 0 {
+		return FormatUnknown
+	}
+
+	hasCount := len(data) >= 84
+	var expected int64
+	if hasCount {
+		expected = binaryFileSize(binary.LittleEndian.Uint32(data[80:84]))
+	}
+
+	text := bytes.TrimPrefix(data, utf8BOM)
+	text = bytes.TrimLeft(text, " \t\r\n")
+	if len(text) >= 5 && bytes.EqualFold(text[:5], []byte("solid")) {
+		// A size that matches the declared count exactly is conclusive
+		if hasCount && size == expected {
+			return FormatBinary
+		}
+		if !hasCount || hasASCIIKeyword(data) {
+			return FormatASCII
+		}
+		return FormatBinary
+	}
+
+	if hasCount && (size < 0 || size >= expected) {
+		return FormatBinary
+	}
+	return FormatUnknown
+}
+
+// hasASCIIKeyword reports whether data contains a keyword that only appears
+// in the body of an ASCII STL file
+func hasASCIIKeyword(data []byte) bool {
+	lower := bytes.ToLower(data)
+	return bytes.Contains(lower, []byte("facet")) || bytes.Contains(lower, []byte("endsolid"))
+}
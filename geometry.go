@@ -0,0 +1,388 @@
+package stl
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// SurfaceArea returns the total surface area of the given triangles.
+// The area is computed from the vertices; the stored normals are ignored.
+func SurfaceArea(triangles []Triangle) float64 {
+	var area float64
+	for i := range triangles {
+		area += triangles[i].Area()
+	}
+	return area
+}
+
+// MeshVolume returns the volume enclosed by the given triangles, computed by
+// summing the signed volumes of the tetrahedra formed by each triangle and a
+// reference point. The result is only meaningful for closed, consistently-wound meshes.
+//
+// Vertices are used at the full float64 precision they were parsed with. To
+// limit cancellation on large or thin-walled parts, the reference point is the
+// first vertex rather than the origin, and the tetrahedra are accumulated with
+// compensated (Kahan-Babuška) summation.
+func MeshVolume(triangles []Triangle) float64 {
+	if len(triangles) == 0 {
+		return 0
+	}
+
+	ref := triangles[0].Vertices[0]
+	var sum kahanSum
+	for i := range triangles {
+		v := triangles[i].Vertices
+		a, b, c := r3.Sub(v[0], ref), r3.Sub(v[1], ref), r3.Sub(v[2], ref)
+		sum.add(r3.Dot(a, r3.Cross(b, c)) / 6)
+	}
+	return math.Abs(sum.value())
+}
+
+// kahanSum accumulates float64 values with Kahan-Babuška compensated
+// summation, tracking the low-order bits lost by each addition
+type kahanSum struct {
+	sum, compensation float64
+}
+
+// add adds x to the sum
+func (k *kahanSum) add(x float64) {
+	t := k.sum + x
+	if math.Abs(k.sum) >= math.Abs(x) {
+		k.compensation += (k.sum - t) + x
+	} else {
+		k.compensation += (x - t) + k.sum
+	}
+	k.sum = t
+}
+
+// value returns the compensated sum
+func (k *kahanSum) value() float64 {
+	return k.sum + k.compensation
+}
+
+// Centroid returns the area-weighted centroid of the surface of the given
+// triangles. Unlike BoundingBox.Center, it accounts for how geometry is
+// distributed within the box. If the total area is zero, the unweighted mean
+// of the triangle centroids is returned instead.
+func Centroid(triangles []Triangle) r3.Vec {
+	if len(triangles) == 0 {
+		return r3.Vec{}
+	}
+
+	var weighted, unweighted r3.Vec
+	var totalArea float64
+	for i := range triangles {
+		c := triangles[i].Centroid()
+		area := triangles[i].Area()
+
+		weighted = r3.Add(weighted, r3.Scale(area, c))
+		unweighted = r3.Add(unweighted, c)
+		totalArea += area
+	}
+
+	if totalArea == 0 {
+		return r3.Scale(1/float64(len(triangles)), unweighted)
+	}
+	return r3.Scale(1/totalArea, weighted)
+}
+
+// MinEdgeLength returns the length of the shortest triangle edge, a cheap
+// proxy for the finest feature or tessellation in the mesh. An empty slice
+// yields 0.
+func MinEdgeLength(triangles []Triangle) float64 {
+	lo, _ := edgeLengthRange(triangles)
+	return lo
+}
+
+// MaxEdgeLength returns the length of the longest triangle edge. An empty
+// slice yields 0.
+func MaxEdgeLength(triangles []Triangle) float64 {
+	_, hi := edgeLengthRange(triangles)
+	return hi
+}
+
+// EdgeLengthHistogram counts the triangle edges falling into each of buckets
+// equal-width length ranges spanning MinEdgeLength to MaxEdgeLength. The last
+// bucket includes the maximum. Edges shared by two triangles are counted once
+// per triangle. It returns nil if buckets is not positive.
+func EdgeLengthHistogram(triangles []Triangle, buckets int) []int {
+	if buckets <= 0 {
+		return nil
+	}
+
+	counts := make([]int, buckets)
+	lo, hi := edgeLengthRange(triangles)
+	width := (hi - lo) / float64(buckets)
+	for i := range triangles {
+		for _, length := range edgeLengths(triangles[i].Vertices) {
+			bucket := buckets - 1
+			if width > 0 {
+				bucket = min(int((length-lo)/width), buckets-1)
+			}
+			counts[bucket]++
+		}
+	}
+	return counts
+}
+
+// edgeLengthRange returns the shortest and longest triangle edge lengths, or
+// zeros for an empty slice
+func edgeLengthRange(triangles []Triangle) (lo, hi float64) {
+	if len(triangles) == 0 {
+		return 0, 0
+	}
+
+	lo = math.Inf(1)
+	for i := range triangles {
+		for _, length := range edgeLengths(triangles[i].Vertices) {
+			lo, hi = min(lo, length), max(hi, length)
+		}
+	}
+	return lo, hi
+}
+
+// edgeLengths returns the lengths of the edges v0-v1, v1-v2, and v2-v0
+func edgeLengths(v [3]r3.Vec) [3]float64 {
+	return [3]float64{
+		r3.Norm(r3.Sub(v[1], v[0])),
+		r3.Norm(r3.Sub(v[2], v[1])),
+		r3.Norm(r3.Sub(v[0], v[2])),
+	}
+}
+
+// DegenerateTriangles returns the indices of triangles whose vertices are
+// coincident or colinear, i.e. whose edge cross product has a magnitude below epsilon.
+func DegenerateTriangles(triangles []Triangle, epsilon float64) []int {
+	var indices []int
+	for i := range triangles {
+		v := triangles[i].Vertices
+		if r3.Norm(r3.Cross(r3.Sub(v[1], v[0]), r3.Sub(v[2], v[0]))) < epsilon {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// FootprintArea returns the area of the shadow the triangles cast on the plane
+// perpendicular to axis (0 = X, 1 = Y, 2 = Z), e.g. axis 2 for the XY print
+// bed footprint. It sums the projected areas of the triangles facing the
+// positive axis direction according to their vertex winding, so
+// back-facing triangles are ignored. Overlap between projected triangles is
+// not removed: the result is exact for closed meshes that every line along
+// axis crosses at most twice, such as convex parts, but counts a region once
+// per upward-facing layer above it for meshes with overhangs or cavities, and
+// so overestimates their footprint. It returns an error if axis is not 0, 1,
+// or 2, since the axis is typically chosen at run time, e.g. from a flag, and
+// an out-of-range value would otherwise silently pick the wrong plane.
+func FootprintArea(triangles []Triangle, axis int) (float64, error) {
+	if axis < 0 || axis > 2 {
+		return 0, fmt.Errorf("invalid axis %d: must be 0, 1, or 2", axis)
+	}
+
+	var area float64
+	for i := range triangles {
+		v := triangles[i].Vertices
+		cross := r3.Cross(r3.Sub(v[1], v[0]), r3.Sub(v[2], v[0]))
+		projected := axisValue(cross, axis) / 2
+		if projected > 0 {
+			area += projected
+		}
+	}
+	return area, nil
+}
+
+// DominantNormal returns the normalized area-weighted sum of the facet
+// normals of the given triangles, computed from their vertex winding. For an
+// open mesh such as a relief or a shell, this is the direction the surface
+// mostly faces. For a closed mesh the facet normals cancel, so the sum is zero
+// up to rounding: the zero vector is returned when its length is negligible
+// next to the total area, and LargestFaceNormal should be used instead to find
+// a flat face to place on the build plate. A mesh with no area yields the zero
+// vector.
+func DominantNormal(triangles []Triangle) r3.Vec {
+	var sum r3.Vec
+	var area float64
+	for i := range triangles {
+		v := triangles[i].Vertices
+		// Half the cross product is the normal scaled by the triangle's area
+		cross := r3.Scale(0.5, r3.Cross(r3.Sub(v[1], v[0]), r3.Sub(v[2], v[0])))
+		norm := r3.Norm(cross)
+		if norm == 0 || math.IsNaN(norm) {
+			continue
+		}
+		sum = r3.Add(sum, cross)
+		area += norm
+	}
+
+	if r3.Norm(sum) <= 1e-9*area {
+		return r3.Vec{}
+	}
+	return r3.Unit(sum)
+}
+
+// LargestFaceNormal returns the unit normal of the largest flat region of the
+// mesh: triangles are grouped by the direction of their normal, computed from
+// the vertex winding and rounded to two decimal places per component, and the
+// area-weighted mean normal of the group with the most area is returned.
+// Unlike DominantNormal, it is meaningful for closed meshes. Snapping the
+// result to -Z places that region on the build plate. A mesh with no area
+// yields the zero vector.
+func LargestFaceNormal(triangles []Triangle) r3.Vec {
+	type normalGroup struct {
+		area float64
+		sum  r3.Vec
+	}
+
+	var groups []normalGroup
+	index := make(map[[3]float64]int)
+	for i := range triangles {
+		v := triangles[i].Vertices
+		cross := r3.Cross(r3.Sub(v[1], v[0]), r3.Sub(v[2], v[0]))
+		norm := r3.Norm(cross)
+		if norm == 0 || math.IsNaN(norm) {
+			continue
+		}
+		normal := r3.Scale(1/norm, cross)
+
+		key := [3]float64{math.Round(normal.X * 100), math.Round(normal.Y * 100), math.Round(normal.Z * 100)}
+		g, ok := index[key]
+		if !ok {
+			g = len(groups)
+			index[key] = g
+			groups = append(groups, normalGroup{})
+		}
+		// Half the cross product is the normal scaled by the triangle's area
+		groups[g].area += norm / 2
+		groups[g].sum = r3.Add(groups[g].sum, r3.Scale(0.5, cross))
+	}
+
+	best := -1
+	for g := range groups {
+		if best < 0 || groups[g].area > groups[best].area {
+			best = g
+		}
+	}
+	if best < 0 {
+		return r3.Vec{}
+	}
+	return r3.Unit(groups[best].sum)
+}
+
+// CheckNormals returns the indices of triangles whose stored Normal differs
+// from the normal implied by their vertex winding by more than angleTolDeg
+// degrees, which catches flipped facets. Triangles with a zero stored normal
+// or degenerate vertices are skipped, as there is nothing to compare.
+func CheckNormals(triangles []Triangle, angleTolDeg float64) []int {
+	minCos := math.Cos(angleTolDeg * math.Pi / 180)
+
+	var indices []int
+	for i := range triangles {
+		stored := triangles[i].Normal
+		computed := faceNormal(triangles[i].Vertices)
+
+		norm := r3.Norm(stored)
+		if norm == 0 || computed == (r3.Vec{}) {
+			continue
+		}
+		if r3.Dot(stored, computed)/norm < minCos {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// BoundingSphere returns a sphere enclosing all vertices of the given triangles,
+// computed with Ritter's algorithm. The sphere is close to, but not guaranteed
+// to be, the minimal enclosing sphere. An empty slice yields a zero sphere.
+func BoundingSphere(triangles []Triangle) (center r3.Vec, radius float64) {
+	if len(triangles) == 0 {
+		return r3.Vec{}, 0
+	}
+
+	// Find a point far from an arbitrary start, then the point farthest from that
+	y := farthestVertex(triangles, triangles[0].Vertices[0])
+	z := farthestVertex(triangles, y)
+
+	center = r3.Scale(0.5, r3.Add(y, z))
+	radius = r3.Norm(r3.Sub(z, y)) / 2
+
+	// Grow the sphere to include any vertex left outside
+	for i := range triangles {
+		for _, v := range triangles[i].Vertices {
+			d := r3.Norm(r3.Sub(v, center))
+			if d > radius {
+				newRadius := (radius + d) / 2
+				center = r3.Add(center, r3.Scale((newRadius-radius)/d, r3.Sub(v, center)))
+				radius = newRadius
+			}
+		}
+	}
+
+	return center, radius
+}
+
+// farthestVertex returns the vertex of the given triangles farthest from p
+func farthestVertex(triangles []Triangle, p r3.Vec) r3.Vec {
+	farthest, maxDist := p, 0.0
+	for i := range triangles {
+		for _, v := range triangles[i].Vertices {
+			if d := r3.Norm2(r3.Sub(v, p)); d > maxDist {
+				farthest, maxDist = v, d
+			}
+		}
+	}
+	return farthest
+}
+
+// RecomputeNormals sets the Normal of each triangle in place to the unit normal
+// implied by its vertex winding (right-hand rule). Degenerate triangles get a
+// zero normal.
+func RecomputeNormals(triangles []Triangle) {
+	for i := range triangles {
+		triangles[i].Normal = faceNormal(triangles[i].Vertices)
+	}
+}
+
+// RecomputedNormals returns a copy of the given triangles with normals
+// recomputed as by RecomputeNormals, leaving the input unchanged.
+func RecomputedNormals(triangles []Triangle) []Triangle {
+	result := make([]Triangle, len(triangles))
+	copy(result, triangles)
+	RecomputeNormals(result)
+	return result
+}
+
+// Area returns the area of the triangle, computed as half the magnitude of the
+// cross product of its edges. Degenerate triangles have an area of 0.
+func (t Triangle) Area() float64 {
+	return triangleArea(t.Vertices)
+}
+
+// Centroid returns the average of the triangle's three vertices
+func (t Triangle) Centroid() r3.Vec {
+	return r3.Scale(1.0/3, r3.Add(r3.Add(t.Vertices[0], t.Vertices[1]), t.Vertices[2]))
+}
+
+// triangleArea returns the area of the triangle spanned by the given vertices.
+// Degenerate triangles have an area of 0.
+func triangleArea(v [3]r3.Vec) float64 {
+	cross := r3.Cross(r3.Sub(v[1], v[0]), r3.Sub(v[2], v[0]))
+	area := r3.Norm(cross) / 2
+	if math.IsNaN(area) {
+		return 0
+	}
+	return area
+}
+
+// faceNormal returns the unit normal of the triangle spanned by the given
+// vertices following the right-hand rule, or the zero vector if it is degenerate
+func faceNormal(v [3]r3.Vec) r3.Vec {
+	cross := r3.Cross(r3.Sub(v[1], v[0]), r3.Sub(v[2], v[0]))
+	norm := r3.Norm(cross)
+	if norm == 0 || math.IsNaN(norm) {
+		return r3.Vec{}
+	}
+	return r3.Scale(1/norm, cross)
+}
@@ -0,0 +1,191 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestSurfaceArea(t *testing.T) {
+	if got := SurfaceArea(unitCube()); math.Abs(got-6) > 1e-12 {
+		t.Errorf("SurfaceArea(unit cube) = %v, want 6", got)
+	}
+	if got := SurfaceArea(nil); got != 0 {
+		t.Errorf("SurfaceArea(nil) = %v, want 0", got)
+	}
+}
+
+// tetrahedron returns the four outward-wound triangles of the corner
+// tetrahedron spanned by the origin and the unit axis points
+func tetrahedron() []Triangle {
+	o, x, y, z := r3.Vec{}, r3.Vec{X: 1}, r3.Vec{Y: 1}, r3.Vec{Z: 1}
+	return []Triangle{
+		triangle(o, y, x),
+		triangle(o, x, z),
+		triangle(o, z, y),
+		triangle(x, y, z),
+	}
+}
+
+// sphere returns a closed, outward-wound UV sphere of radius 1 centered at the
+// origin, with n latitude bands and 2n longitude segments
+func sphere(n int) []Triangle {
+	point := func(i, j int) r3.Vec {
+		theta, phi := math.Pi*float64(i)/float64(n), math.Pi*float64(j)/float64(n)
+		return r3.Vec{X: math.Sin(theta) * math.Cos(phi), Y: math.Sin(theta) * math.Sin(phi), Z: math.Cos(theta)}
+	}
+
+	var triangles []Triangle
+	for i := 0; i < n; i++ {
+		for j := 0; j < 2*n; j++ {
+			a, b, c, d := point(i, j), point(i+1, j), point(i+1, j+1), point(i, j+1)
+			// The poles collapse one triangle of each band to a point
+			if i > 0 {
+				triangles = append(triangles, triangle(a, b, d))
+			}
+			if i < n-1 {
+				triangles = append(triangles, triangle(b, c, d))
+			}
+		}
+	}
+	return triangles
+}
+
+func TestMeshVolume(t *testing.T) {
+	if got := MeshVolume(unitCube()); math.Abs(got-1) > 1e-12 {
+		t.Errorf("MeshVolume(unit cube) = %v, want 1", got)
+	}
+	if got := MeshVolume(tetrahedron()); math.Abs(got-1.0/6) > 1e-12 {
+		t.Errorf("MeshVolume(tetrahedron) = %v, want 1/6", got)
+	}
+
+	shifted := translate(tetrahedron(), r3.Vec{X: 1000, Y: -500, Z: 250})
+	if got := MeshVolume(shifted); math.Abs(got-1.0/6) > 1e-9 {
+		t.Errorf("MeshVolume(shifted tetrahedron) = %v, want 1/6", got)
+	}
+
+	// The inscribed polyhedron falls short of the sphere by an amount that
+	// shrinks with the square of the tessellation step
+	for _, n := range []int{8, 16, 32, 64} {
+		want := 4 * math.Pi / 3
+		tol := 8 * math.Pi / float64(n*n)
+		if got := MeshVolume(sphere(n)); got > want || want-got > tol {
+			t.Errorf("MeshVolume(sphere(%d)) = %v, want %v within %v", n, got, want, tol)
+		}
+	}
+
+	if got := MeshVolume(nil); got != 0 {
+		t.Errorf("MeshVolume(nil) = %v, want 0", got)
+	}
+}
+
+// translate returns a copy of triangles moved by offset
+func translate(triangles []Triangle, offset r3.Vec) []Triangle {
+	result := make([]Triangle, len(triangles))
+	for i := range triangles {
+		result[i] = triangles[i]
+		for j := range result[i].Vertices {
+			result[i].Vertices[j] = r3.Add(result[i].Vertices[j], offset)
+		}
+	}
+	return result
+}
+
+func TestBoundingSphere(t *testing.T) {
+	cube := translate(unitCube(), r3.Vec{X: -0.5, Y: -0.5, Z: -0.5})
+	center, radius := BoundingSphere(cube)
+	if r3.Norm(center) > 1e-12 {
+		t.Errorf("centered cube: center = %v, want origin", center)
+	}
+	if want := math.Sqrt(3) / 2; math.Abs(radius-want) > 1e-12 {
+		t.Errorf("centered cube: radius = %v, want %v", radius, want)
+	}
+
+	single := []Triangle{triangle(r3.Vec{}, r3.Vec{X: 1}, r3.Vec{Y: 1})}
+	center, radius = BoundingSphere(single)
+	if want := math.Sqrt2 / 2; math.Abs(radius-want) > 1e-12 {
+		t.Errorf("single triangle: radius = %v, want %v", radius, want)
+	}
+	for _, v := range single[0].Vertices {
+		if d := r3.Norm(r3.Sub(v, center)); d > radius+1e-12 {
+			t.Errorf("single triangle: vertex %v is %v from center, outside radius %v", v, d, radius)
+		}
+	}
+
+	center, radius = BoundingSphere(nil)
+	if center != (r3.Vec{}) || radius != 0 {
+		t.Errorf("BoundingSphere(nil) = %v, %v, want zero sphere", center, radius)
+	}
+}
+
+func TestTriangleArea(t *testing.T) {
+	tests := []struct {
+		name string
+		tri  Triangle
+		want float64
+	}{
+		{"right triangle", triangle(r3.Vec{}, r3.Vec{X: 1}, r3.Vec{Y: 1}), 0.5},
+		{"colinear", triangle(r3.Vec{}, r3.Vec{X: 1}, r3.Vec{X: 2}), 0},
+		{"coincident", triangle(r3.Vec{X: 1}, r3.Vec{X: 1}, r3.Vec{X: 1}), 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.tri.Area(); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: Area = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDominantNormal(t *testing.T) {
+	cube := unitCube()
+	if got := DominantNormal(cube); got != (r3.Vec{}) {
+		t.Errorf("closed cube: DominantNormal = %v, want zero vector", got)
+	}
+
+	// Without its two top triangles, the cube is a box open at +Z whose
+	// remaining normals sum to -Z
+	var open []Triangle
+	for _, tri := range cube {
+		if tri.Normal.Z <= 0.5 {
+			open = append(open, tri)
+		}
+	}
+	if got := DominantNormal(open); r3.Norm(r3.Sub(got, r3.Vec{Z: -1})) > 1e-12 {
+		t.Errorf("open box: DominantNormal = %v, want -Z", got)
+	}
+
+	if got := DominantNormal(nil); got != (r3.Vec{}) {
+		t.Errorf("DominantNormal(nil) = %v, want zero vector", got)
+	}
+}
+
+func TestLargestFaceNormal(t *testing.T) {
+	// The slanted face is the largest of the tetrahedron's faces
+	want := r3.Unit(r3.Vec{X: 1, Y: 1, Z: 1})
+	if got := LargestFaceNormal(tetrahedron()); r3.Norm(r3.Sub(got, want)) > 1e-12 {
+		t.Errorf("LargestFaceNormal(tetrahedron) = %v, want %v", got, want)
+	}
+
+	if got := LargestFaceNormal(nil); got != (r3.Vec{}) {
+		t.Errorf("LargestFaceNormal(nil) = %v, want zero vector", got)
+	}
+}
+
+func TestFootprintArea(t *testing.T) {
+	for axis := 0; axis < 3; axis++ {
+		got, err := FootprintArea(unitCube(), axis)
+		if err != nil {
+			t.Fatalf("FootprintArea(unit cube, %d): %v", axis, err)
+		}
+		if math.Abs(got-1) > 1e-12 {
+			t.Errorf("FootprintArea(unit cube, %d) = %v, want 1", axis, got)
+		}
+	}
+
+	for _, axis := range []int{-1, 3} {
+		if _, err := FootprintArea(unitCube(), axis); err == nil {
+			t.Errorf("FootprintArea(unit cube, %d): got nil error", axis)
+		}
+	}
+}
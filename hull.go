@@ -0,0 +1,297 @@
+package stl
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// ConvexHull returns the triangulated convex hull of the vertices of the given
+// triangles, computed with the quickhull algorithm. Hull faces are wound
+// counter-clockwise when viewed from outside and carry outward unit normals.
+// The hull has the same bounding box as the mesh but is usually far smaller,
+// making it a good collision proxy. It returns nil if the vertices do not span
+// a volume, e.g. for an empty or perfectly flat mesh.
+func ConvexHull(triangles []Triangle) []Triangle {
+	points := uniqueVertices(triangles)
+	h := newHull(points)
+	if h == nil {
+		return nil
+	}
+	h.build()
+	return h.triangles()
+}
+
+// uniqueVertices returns the distinct vertices of the given triangles in the
+// order they first appear
+func uniqueVertices(triangles []Triangle) []r3.Vec {
+	seen := make(map[r3.Vec]struct{})
+	var points []r3.Vec
+	for i := range triangles {
+		for _, v := range triangles[i].Vertices {
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				points = append(points, v)
+			}
+		}
+	}
+	return points
+}
+
+// hullFace is a face of a convex hull under construction
+type hullFace struct {
+	// v holds indices into hull.points, counter-clockwise from outside
+	v      [3]int
+	normal r3.Vec
+	offset float64
+	// outside holds the points in front of the face not yet on the hull
+	outside []int
+	// visible is scratch space marking faces seen from the current apex
+	visible bool
+	deleted bool
+}
+
+// distance returns the signed distance of p in front of the face's plane
+func (f *hullFace) distance(p r3.Vec) float64 {
+	return r3.Dot(f.normal, p) - f.offset
+}
+
+// hull holds the state of a quickhull computation
+type hull struct {
+	points []r3.Vec
+	faces  []*hullFace
+	// edges maps each directed edge of a live face to that face's index, so
+	// the neighbour across edge (a, b) is found under (b, a)
+	edges   map[[2]int]int
+	epsilon float64
+}
+
+// newHull returns a hull initialised with a tetrahedron of extreme points,
+// or nil if the points do not span a volume
+func newHull(points []r3.Vec) *hull {
+	if len(points) < 4 {
+		return nil
+	}
+
+	// Tolerance for coplanarity, relative to the magnitude of the coordinates
+	var maxX, maxY, maxZ float64
+	for _, p := range points {
+		maxX = math.Max(maxX, math.Abs(p.X))
+		maxY = math.Max(maxY, math.Abs(p.Y))
+		maxZ = math.Max(maxZ, math.Abs(p.Z))
+	}
+	h := &hull{
+		points:  points,
+		edges:   make(map[[2]int]int),
+		epsilon: 3 * 0x1p-52 * (maxX + maxY + maxZ),
+	}
+
+	// The two most distant of the extreme points along each axis
+	var extremes [6]int
+	for i, p := range points {
+		for axis := 0; axis < 3; axis++ {
+			if axisValue(p, axis) < axisValue(points[extremes[2*axis]], axis) {
+				extremes[2*axis] = i
+			}
+			if axisValue(p, axis) > axisValue(points[extremes[2*axis+1]], axis) {
+				extremes[2*axis+1] = i
+			}
+		}
+	}
+	a, b := extremes[0], extremes[1]
+	best := 0.0
+	for i := range extremes {
+		for j := i + 1; j < len(extremes); j++ {
+			if d := r3.Norm2(r3.Sub(points[extremes[i]], points[extremes[j]])); d > best {
+				best, a, b = d, extremes[i], extremes[j]
+			}
+		}
+	}
+	if math.Sqrt(best) <= h.epsilon {
+		return nil
+	}
+
+	// The point farthest from the line through a and b
+	dir := r3.Unit(r3.Sub(points[b], points[a]))
+	c, best := -1, 0.0
+	for i, p := range points {
+		if d := r3.Norm(r3.Cross(dir, r3.Sub(p, points[a]))); d > best {
+			best, c = d, i
+		}
+	}
+	if best <= h.epsilon {
+		return nil
+	}
+
+	// The point farthest from the plane through a, b and c
+	normal := r3.Unit(r3.Cross(r3.Sub(points[b], points[a]), r3.Sub(points[c], points[a])))
+	d, best := -1, 0.0
+	for i, p := range points {
+		if dist := math.Abs(r3.Dot(normal, r3.Sub(p, points[a]))); dist > best {
+			best, d = dist, i
+		}
+	}
+	if best <= h.epsilon {
+		return nil
+	}
+
+	// Orient the tetrahedron's faces away from its fourth vertex
+	tetra := [4]int{a, b, c, d}
+	for i, opposite := range tetra {
+		var v [3]int
+		k := 0
+		for j, index := range tetra {
+			if j != i {
+				v[k] = index
+				k++
+			}
+		}
+		face := h.newFace(v)
+		if face.distance(points[opposite]) > 0 {
+			v[1], v[2] = v[2], v[1]
+			face = h.newFace(v)
+		}
+		h.addFace(face)
+	}
+
+	// Assign every other point to a face it lies in front of
+	all := make([]int, 0, len(points))
+	for i := range points {
+		if i != a && i != b && i != c && i != d {
+			all = append(all, i)
+		}
+	}
+	h.assign(all, h.faces)
+	return h
+}
+
+// newFace returns a face through the given points with its plane computed
+func (h *hull) newFace(v [3]int) *hullFace {
+	normal := faceNormal([3]r3.Vec{h.points[v[0]], h.points[v[1]], h.points[v[2]]})
+	return &hullFace{v: v, normal: normal, offset: r3.Dot(normal, h.points[v[0]])}
+}
+
+// addFace adds a face to the hull and records its edges
+func (h *hull) addFace(f *hullFace) {
+	index := len(h.faces)
+	h.faces = append(h.faces, f)
+	for i := 0; i < 3; i++ {
+		h.edges[[2]int{f.v[i], f.v[(i+1)%3]}] = index
+	}
+}
+
+// neighbour returns the live face across the edge from a to b of another
+// face, or nil if no face shares that edge
+func (h *hull) neighbour(a, b int) *hullFace {
+	index, ok := h.edges[[2]int{b, a}]
+	if !ok {
+		return nil
+	}
+	return h.faces[index]
+}
+
+// assign adds each point to the outside set of the face it lies farthest in
+// front of; points behind every face are inside the hull and dropped
+func (h *hull) assign(points []int, faces []*hullFace) {
+	for _, p := range points {
+		var best *hullFace
+		bestDistance := h.epsilon
+		for _, f := range faces {
+			if f.deleted {
+				continue
+			}
+			if d := f.distance(h.points[p]); d > bestDistance {
+				best, bestDistance = f, d
+			}
+		}
+		if best != nil {
+			best.outside = append(best.outside, p)
+		}
+	}
+}
+
+// build repeatedly adds the farthest outside point of a face to the hull
+// until no points lie outside it
+func (h *hull) build() {
+	for i := 0; i < len(h.faces); i++ {
+		face := h.faces[i]
+		for !face.deleted && len(face.outside) > 0 {
+			h.addPoint(face)
+		}
+	}
+}
+
+// addPoint adds the point of face's outside set farthest from its plane,
+// replacing every face visible from it with a cone of faces to the point
+func (h *hull) addPoint(face *hullFace) {
+	apex := face.outside[0]
+	for _, p := range face.outside[1:] {
+		if face.distance(h.points[p]) > face.distance(h.points[apex]) {
+			apex = p
+		}
+	}
+	point := h.points[apex]
+
+	// Find the faces visible from the apex by walking outwards from face
+	visible := []*hullFace{face}
+	face.visible = true
+	for i := 0; i < len(visible); i++ {
+		f := visible[i]
+		for j := 0; j < 3; j++ {
+			neighbour := h.neighbour(f.v[j], f.v[(j+1)%3])
+			if neighbour != nil && !neighbour.visible && neighbour.distance(point) > h.epsilon {
+				neighbour.visible = true
+				visible = append(visible, neighbour)
+			}
+		}
+	}
+
+	// The horizon is formed by edges between visible and hidden faces
+	var horizon [][2]int
+	for _, f := range visible {
+		for j := 0; j < 3; j++ {
+			edge := [2]int{f.v[j], f.v[(j+1)%3]}
+			if neighbour := h.neighbour(edge[0], edge[1]); neighbour == nil || !neighbour.visible {
+				horizon = append(horizon, edge)
+			}
+		}
+	}
+
+	// Remove the visible faces, keeping their outside points for reassignment
+	var orphans []int
+	for _, f := range visible {
+		f.deleted = true
+		for j := 0; j < 3; j++ {
+			delete(h.edges, [2]int{f.v[j], f.v[(j+1)%3]})
+		}
+		for _, p := range f.outside {
+			if p != apex {
+				orphans = append(orphans, p)
+			}
+		}
+		f.outside = nil
+	}
+
+	cone := make([]*hullFace, 0, len(horizon))
+	for _, edge := range horizon {
+		f := h.newFace([3]int{edge[0], edge[1], apex})
+		h.addFace(f)
+		cone = append(cone, f)
+	}
+	h.assign(orphans, cone)
+}
+
+// triangles returns the live faces of the hull
+func (h *hull) triangles() []Triangle {
+	var result []Triangle
+	for _, f := range h.faces {
+		if f.deleted {
+			continue
+		}
+		result = append(result, Triangle{
+			Normal:   f.normal,
+			Vertices: [3]r3.Vec{h.points[f.v[0]], h.points[f.v[1]], h.points[f.v[2]]},
+		})
+	}
+	return result
+}
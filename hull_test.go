@@ -0,0 +1,81 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// checkCubeHull reports whether hull is a closed, outward-wound hull of the
+// unit cube
+func checkCubeHull(t *testing.T, label string, hull []Triangle) {
+	t.Helper()
+
+	if len(hull) != 12 {
+		t.Fatalf("%s: got %d faces, want 12", label, len(hull))
+	}
+	if got, want := BoundingBoxFromTriangles(hull), BoundingBoxFromTriangles(unitCube()); !got.Equal(want) {
+		t.Errorf("%s: got box %+v, want %+v", label, *got, *want)
+	}
+	if watertight, err := IsWatertight(hull, 0); !watertight {
+		t.Errorf("%s: hull is not watertight: %v", label, err)
+	}
+	if v := MeshVolume(hull); math.Abs(v-1) > 1e-12 {
+		t.Errorf("%s: hull volume = %v, want 1", label, v)
+	}
+
+	// Every face points away from the cube's center
+	center := r3.Vec{X: 0.5, Y: 0.5, Z: 0.5}
+	for i, f := range hull {
+		if r3.Dot(f.Normal, r3.Sub(f.Vertices[0], center)) <= 0 {
+			t.Errorf("%s: face %d normal %v points inwards", label, i, f.Normal)
+		}
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	cube := unitCube()
+	checkCubeHull(t, "cube", ConvexHull(cube))
+
+	// Interior points and points on the faces do not change the hull
+	extra := append(unitCube(),
+		triangle(r3.Vec{X: 0.5, Y: 0.5, Z: 0.5}, r3.Vec{X: 0.2, Y: 0.7, Z: 0.4}, r3.Vec{X: 0.9, Y: 0.1, Z: 0.3}),
+		triangle(r3.Vec{X: 0.5, Y: 0.5}, r3.Vec{X: 0.5, Y: 0.5, Z: 1}, r3.Vec{X: 1, Y: 0.25, Z: 0.75}),
+	)
+	checkCubeHull(t, "cube with extra points", ConvexHull(extra))
+}
+
+func TestConvexHullDegenerate(t *testing.T) {
+	square := []Triangle{
+		triangle(r3.Vec{}, r3.Vec{X: 1}, r3.Vec{X: 1, Y: 1}),
+		triangle(r3.Vec{}, r3.Vec{X: 1, Y: 1}, r3.Vec{Y: 1}),
+		triangle(r3.Vec{X: 0.5, Y: 0.5}, r3.Vec{X: 0.25, Y: 0.75}, r3.Vec{X: 2, Y: 3}),
+	}
+	line := []Triangle{
+		triangle(r3.Vec{}, r3.Vec{X: 1, Y: 1, Z: 1}, r3.Vec{X: 2, Y: 2, Z: 2}),
+		triangle(r3.Vec{X: 3, Y: 3, Z: 3}, r3.Vec{X: -1, Y: -1, Z: -1}, r3.Vec{X: 0.5, Y: 0.5, Z: 0.5}),
+	}
+
+	for label, triangles := range map[string][]Triangle{"coplanar": square, "collinear": line, "empty": nil} {
+		if hull := ConvexHull(triangles); hull != nil {
+			t.Errorf("%s: got %d faces, want nil", label, len(hull))
+		}
+	}
+}
+
+func TestConvexHullSphere(t *testing.T) {
+	// A tessellated sphere is convex, so it is its own hull once the points
+	// repeated at its poles and seam are welded
+	mesh := sphere(12)
+	hull := ConvexHull(mesh)
+	if got, want := MeshVolume(hull), MeshVolume(mesh); math.Abs(got-want) > 1e-9 {
+		t.Errorf("hull volume = %v, want %v", got, want)
+	}
+	if got, want := UniqueVertices(hull, 1e-9), UniqueVertices(mesh, 1e-9); got != want {
+		t.Errorf("hull has %d vertices, want %d", got, want)
+	}
+	if watertight, err := IsWatertight(hull, 0); !watertight {
+		t.Errorf("hull is not watertight: %v", err)
+	}
+}
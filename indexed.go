@@ -0,0 +1,43 @@
+package stl
+
+import (
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// IndexedMesh is a mesh whose faces index into a shared vertex list.
+// It avoids duplicating vertices shared between triangles.
+type IndexedMesh struct {
+	Vertices []r3.Vec
+	// Faces holds the indices into Vertices of each triangle's corners,
+	// in winding order
+	Faces [][3]int
+}
+
+// NewIndexedMesh builds an indexed mesh from the given triangles, welding
+// vertices within tol of each other into a single shared vertex. A tol of 0
+// or less only welds exactly coincident vertices.
+func NewIndexedMesh(triangles []Triangle, tol float64) IndexedMesh {
+	welder := newVertexWelder(tol)
+	faces := make([][3]int, len(triangles))
+	for i := range triangles {
+		for j, v := range triangles[i].Vertices {
+			faces[i][j] = welder.add(v)
+		}
+	}
+
+	return IndexedMesh{Vertices: welder.vertices, Faces: faces}
+}
+
+// Triangles expands the mesh back into a flat slice of triangles.
+// Normals are recomputed from the winding of each face.
+func (m IndexedMesh) Triangles() []Triangle {
+	triangles := make([]Triangle, len(m.Faces))
+	for i, f := range m.Faces {
+		t := &triangles[i]
+		for j, index := range f {
+			t.Vertices[j] = m.Vertices[index]
+		}
+		t.Normal = faceNormal(t.Vertices)
+	}
+	return triangles
+}
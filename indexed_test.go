@@ -0,0 +1,42 @@
+package stl
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestIndexedMeshRoundTrip(t *testing.T) {
+	cube := unitCube()
+
+	mesh := NewIndexedMesh(cube, 0)
+	if len(mesh.Vertices) != 8 || len(mesh.Faces) != 12 {
+		t.Fatalf("got %d vertices and %d faces, want 8 and 12", len(mesh.Vertices), len(mesh.Faces))
+	}
+
+	got := mesh.Triangles()
+	if len(got) != len(cube) {
+		t.Fatalf("got %d triangles, want %d", len(got), len(cube))
+	}
+	for i := range cube {
+		if got[i] != cube[i] {
+			t.Errorf("triangle %d: got %+v, want %+v", i, got[i], cube[i])
+		}
+	}
+
+	// Vertices within the tolerance weld to the first one seen
+	noisy := unitCube()
+	noisy[1].Vertices[0] = r3.Add(noisy[1].Vertices[0], r3.Vec{X: 1e-9})
+	if n := len(NewIndexedMesh(noisy, 0).Vertices); n != 9 {
+		t.Errorf("exact welding gave %d vertices, want 9", n)
+	}
+	welded := NewIndexedMesh(noisy, 1e-6)
+	if len(welded.Vertices) != 8 {
+		t.Fatalf("welding within 1e-6 gave %d vertices, want 8", len(welded.Vertices))
+	}
+	for i, tri := range welded.Triangles() {
+		if tri != cube[i] {
+			t.Errorf("welded triangle %d: got %+v, want %+v", i, tri, cube[i])
+		}
+	}
+}
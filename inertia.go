@@ -0,0 +1,112 @@
+package stl
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// canonicalCovariance is the covariance of the tetrahedron with vertices at
+// the origin and the three unit vectors, per unit of its determinant
+var canonicalCovariance = mat.NewDense(3, 3, []float64{
+	2.0 / 120, 1.0 / 120, 1.0 / 120,
+	1.0 / 120, 2.0 / 120, 1.0 / 120,
+	1.0 / 120, 1.0 / 120, 2.0 / 120,
+})
+
+// InertiaTensor returns the inertia tensor of the solid enclosed by the given
+// triangles about its center of mass, assuming uniform density, along with
+// the center of mass. It integrates exactly over the tetrahedra formed by
+// each triangle and a reference point, so the mesh must be closed and
+// consistently wound; either winding direction gives the same result. An
+// empty mesh or one enclosing no volume yields a zero tensor and the surface
+// centroid.
+func InertiaTensor(triangles []Triangle, density float64) (mat.Symmetric, r3.Vec) {
+	tensor := mat.NewSymDense(3, nil)
+	if len(triangles) == 0 {
+		return tensor, r3.Vec{}
+	}
+
+	// Integrate relative to the first vertex, as in MeshVolume, to limit
+	// cancellation far from the origin
+	ref := triangles[0].Vertices[0]
+	var covariance, tetra, vertices mat.Dense
+	covariance.ReuseAs(3, 3)
+	vertices.ReuseAs(3, 3)
+	var volume float64
+	var moment r3.Vec
+	for i := range triangles {
+		v := triangles[i].Vertices
+		a, b, c := r3.Sub(v[0], ref), r3.Sub(v[1], ref), r3.Sub(v[2], ref)
+		det := r3.Dot(a, r3.Cross(b, c))
+		if det == 0 {
+			continue
+		}
+
+		// The covariance of the tetrahedron is det * A * C * A^T, where the
+		// columns of A are its vertices and C is the canonical covariance
+		for k, p := range [3]r3.Vec{a, b, c} {
+			vertices.Set(0, k, p.X)
+			vertices.Set(1, k, p.Y)
+			vertices.Set(2, k, p.Z)
+		}
+		tetra.Product(&vertices, canonicalCovariance, vertices.T())
+		tetra.Scale(det, &tetra)
+		covariance.Add(&covariance, &tetra)
+
+		volume += det / 6
+		moment = r3.Add(moment, r3.Scale(det/24, r3.Add(a, r3.Add(b, c))))
+	}
+	if volume == 0 {
+		return tensor, Centroid(triangles)
+	}
+
+	// Move the covariance from the reference point to the center of mass
+	offset := r3.Scale(1/volume, moment)
+	d := [3]float64{offset.X, offset.Y, offset.Z}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			covariance.Set(i, j, covariance.At(i, j)-volume*d[i]*d[j])
+		}
+	}
+
+	// Inward-facing meshes have negative volume and covariance, so flip the
+	// sign to make the result independent of winding
+	scale := density
+	if volume < 0 {
+		scale = -density
+	}
+	trace := covariance.At(0, 0) + covariance.At(1, 1) + covariance.At(2, 2)
+	for i := 0; i < 3; i++ {
+		for j := i; j < 3; j++ {
+			value := -covariance.At(i, j)
+			if i == j {
+				value += trace
+			}
+			tensor.SetSym(i, j, scale*value)
+		}
+	}
+	return tensor, r3.Add(ref, offset)
+}
+
+// PrincipalAxes diagonalizes an inertia tensor such as one returned by
+// InertiaTensor, returning the principal moments in ascending order and the
+// corresponding unit axes. The coordinate axes and the diagonal of inertia
+// are returned if the decomposition fails.
+func PrincipalAxes(inertia mat.Symmetric) (moments [3]float64, axes [3]r3.Vec) {
+	var eig mat.EigenSym
+	if !eig.Factorize(inertia, true) {
+		for k := 0; k < 3; k++ {
+			moments[k] = inertia.At(k, k)
+		}
+		return moments, [3]r3.Vec{{X: 1}, {Y: 1}, {Z: 1}}
+	}
+
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+	values := eig.Values(nil)
+	for k := 0; k < 3; k++ {
+		moments[k] = values[k]
+		axes[k] = r3.Unit(r3.Vec{X: vectors.At(0, k), Y: vectors.At(1, k), Z: vectors.At(2, k)})
+	}
+	return moments, axes
+}
@@ -0,0 +1,51 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestInertiaTensor(t *testing.T) {
+	// Far from the origin, to check the shift to the center of mass
+	cube := translate(unitCube(), r3.Vec{X: 100, Y: -50, Z: 7})
+	tensor, center := InertiaTensor(cube, 1)
+	if want := (r3.Vec{X: 100.5, Y: -49.5, Z: 7.5}); r3.Norm(r3.Sub(center, want)) > 1e-9 {
+		t.Errorf("center of mass = %v, want %v", center, want)
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			want := 0.0
+			if i == j {
+				want = 1.0 / 6
+			}
+			if got := tensor.At(i, j); math.Abs(got-want) > 1e-9 {
+				t.Errorf("I[%d][%d] = %v, want %v", i, j, got, want)
+			}
+		}
+	}
+
+	// Inside-out winding gives the same tensor
+	inverted, _ := InertiaTensor(flipped(cube, upTo(12)...), 1)
+	if got := inverted.At(0, 0); math.Abs(got-1.0/6) > 1e-9 {
+		t.Errorf("inverted: I[0][0] = %v, want 1/6", got)
+	}
+}
+
+func TestPrincipalAxes(t *testing.T) {
+	// A 1x2x3 box of mass 6 has moments m(b²+c²)/12 about each axis
+	tensor, _ := InertiaTensor(cuboid(r3.Vec{X: 1, Y: 2, Z: 3}), 1)
+	moments, axes := PrincipalAxes(tensor)
+
+	wantMoments := [3]float64{2.5, 5, 6.5}
+	wantAxes := [3]r3.Vec{{Z: 1}, {Y: 1}, {X: 1}}
+	for k := range moments {
+		if math.Abs(moments[k]-wantMoments[k]) > 1e-9 {
+			t.Errorf("moment %d = %v, want %v", k, moments[k], wantMoments[k])
+		}
+		if math.Abs(math.Abs(r3.Dot(axes[k], wantAxes[k]))-1) > 1e-9 {
+			t.Errorf("axis %d = %v, want ±%v", k, axes[k], wantAxes[k])
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package stl
+
+import (
+	"encoding/json"
+)
+
+// jsonVec is the JSON representation of a 3D point or size.
+// Coordinates use float32 to match the precision of BoundingBox.
+type jsonVec struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+	Z float32 `json:"z"`
+}
+
+// jsonBoundingBox is the JSON representation of a BoundingBox
+type jsonBoundingBox struct {
+	Min        jsonVec `json:"min"`
+	Max        jsonVec `json:"max"`
+	Dimensions jsonVec `json:"dimensions"`
+	Center     jsonVec `json:"center"`
+	Volume     float32 `json:"volume"`
+	Partial    bool    `json:"partial,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The output includes the computed
+// dimensions and volume alongside the stored min, max, and center, and a
+// partial field only for partial boxes.
+func (bb BoundingBox) MarshalJSON() ([]byte, error) {
+	width, height, depth := bb.Dimensions()
+
+	return json.Marshal(jsonBoundingBox{
+		Min:        jsonVec{X: bb.MinX, Y: bb.MinY, Z: bb.MinZ},
+		Max:        jsonVec{X: bb.MaxX, Y: bb.MaxY, Z: bb.MaxZ},
+		Dimensions: jsonVec{X: width, Y: height, Z: depth},
+		Center:     jsonVec{X: float32(bb.Center.X), Y: float32(bb.Center.Y), Z: float32(bb.Center.Z)},
+		Volume:     bb.Volume(),
+		Partial:    bb.Partial,
+	})
+}
@@ -0,0 +1,167 @@
+package stl
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// VertexKDTree is a k-d tree over mesh vertices, answering nearest-neighbor
+// and radius queries in roughly O(log n) time each
+type VertexKDTree struct {
+	vertices []r3.Vec
+	// order holds vertex indices arranged as an implicit balanced tree: the
+	// median of each range order[lo:hi] is its node, split along axis
+	// depth % 3, with smaller values before it and larger values after
+	order []int
+}
+
+// NewVertexKDTree builds a k-d tree over the distinct vertices of the given
+// triangles. Query results index into Vertices, which lists each distinct
+// vertex once in the order it first appears.
+func NewVertexKDTree(triangles []Triangle) *VertexKDTree {
+	return newVertexKDTree(uniqueVertices(triangles))
+}
+
+// NewVertexKDTreeFromMesh builds a k-d tree over the vertices of mesh, so
+// query results index into mesh.Vertices. The vertices are referenced, not
+// copied, and must not be modified while the tree is in use.
+func NewVertexKDTreeFromMesh(mesh IndexedMesh) *VertexKDTree {
+	return newVertexKDTree(mesh.Vertices)
+}
+
+// newVertexKDTree builds a k-d tree over the given vertices
+func newVertexKDTree(vertices []r3.Vec) *VertexKDTree {
+	kd := &VertexKDTree{vertices: vertices, order: make([]int, len(vertices))}
+	for i := range kd.order {
+		kd.order[i] = i
+	}
+	kd.build(0, len(vertices), 0)
+	return kd
+}
+
+// build arranges order[lo:hi] as the subtree at the given depth
+func (kd *VertexKDTree) build(lo, hi, depth int) {
+	if hi-lo <= 1 {
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	kd.selectMedian(lo, hi, mid, depth%3)
+	kd.build(lo, mid, depth+1)
+	kd.build(mid+1, hi, depth+1)
+}
+
+// selectMedian partially orders order[lo:hi] along axis so that the vertex at
+// mid is in its sorted position, with no larger values before it and no
+// smaller values after it. It uses quickselect with a three-way partition,
+// so flat meshes sharing one coordinate do not degrade to quadratic time.
+func (kd *VertexKDTree) selectMedian(lo, hi, mid, axis int) {
+	value := func(k int) float64 { return axisValue(kd.vertices[kd.order[k]], axis) }
+	swap := func(a, b int) { kd.order[a], kd.order[b] = kd.order[b], kd.order[a] }
+
+	for hi-lo > 1 {
+		// Partition into values below, equal to, and above the middle element
+		pivot := value(lo + (hi-lo)/2)
+		lt, k, gt := lo, lo, hi
+		for k < gt {
+			switch v := value(k); {
+			case v < pivot:
+				swap(k, lt)
+				lt++
+				k++
+			case v > pivot:
+				gt--
+				swap(k, gt)
+			default:
+				k++
+			}
+		}
+
+		switch {
+		case mid < lt:
+			hi = lt
+		case mid >= gt:
+			lo = gt
+		default:
+			return
+		}
+	}
+}
+
+// Vertices returns the vertices the tree was built over, indexed as in query
+// results
+func (kd *VertexKDTree) Vertices() []r3.Vec {
+	return kd.vertices
+}
+
+// Nearest returns the index of the vertex closest to p and its distance from
+// p. Ties favor the lower index. An empty tree returns -1 and +Inf.
+func (kd *VertexKDTree) Nearest(p r3.Vec) (index int, dist float64) {
+	index, best := -1, math.Inf(1)
+	kd.nearest(p, 0, len(kd.order), 0, &index, &best)
+	return index, math.Sqrt(best)
+}
+
+// nearest searches the subtree order[lo:hi] at the given depth, updating the
+// best index and squared distance found so far
+func (kd *VertexKDTree) nearest(p r3.Vec, lo, hi, depth int, index *int, best *float64) {
+	if lo >= hi {
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	i := kd.order[mid]
+	if d := r3.Norm2(r3.Sub(kd.vertices[i], p)); d < *best || (d == *best && i < *index) {
+		*index, *best = i, d
+	}
+
+	// Search the side containing p first, then the other side only if the
+	// splitting plane is closer than the best match
+	axis := depth % 3
+	diff := axisValue(p, axis) - axisValue(kd.vertices[i], axis)
+	if diff < 0 {
+		kd.nearest(p, lo, mid, depth+1, index, best)
+		if diff*diff <= *best {
+			kd.nearest(p, mid+1, hi, depth+1, index, best)
+		}
+	} else {
+		kd.nearest(p, mid+1, hi, depth+1, index, best)
+		if diff*diff <= *best {
+			kd.nearest(p, lo, mid, depth+1, index, best)
+		}
+	}
+}
+
+// WithinRadius returns the indices of all vertices within distance r of p,
+// in ascending order
+func (kd *VertexKDTree) WithinRadius(p r3.Vec, r float64) []int {
+	var indices []int
+	kd.withinRadius(p, r, 0, len(kd.order), 0, &indices)
+	sort.Ints(indices)
+	return indices
+}
+
+// withinRadius appends the vertices of the subtree order[lo:hi] at the given
+// depth that lie within r of p to indices
+func (kd *VertexKDTree) withinRadius(p r3.Vec, r float64, lo, hi, depth int, indices *[]int) {
+	if lo >= hi {
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	i := kd.order[mid]
+	if r3.Norm2(r3.Sub(kd.vertices[i], p)) <= r*r {
+		*indices = append(*indices, i)
+	}
+
+	axis := depth % 3
+	diff := axisValue(p, axis) - axisValue(kd.vertices[i], axis)
+	if diff <= r {
+		kd.withinRadius(p, r, lo, mid, depth+1, indices)
+	}
+	if diff >= -r {
+		kd.withinRadius(p, r, mid+1, hi, depth+1, indices)
+	}
+}
@@ -0,0 +1,75 @@
+package stl
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// randomPoints returns n points on a coarse grid, so many share coordinates
+// along each axis, with every fourth point an exact duplicate of an earlier one
+func randomPoints(rng *rand.Rand, n int) []r3.Vec {
+	points := make([]r3.Vec, n)
+	for i := range points {
+		if i > 0 && i%4 == 0 {
+			points[i] = points[rng.IntN(i)]
+			continue
+		}
+		points[i] = r3.Vec{X: float64(rng.IntN(10)), Y: float64(rng.IntN(10)), Z: float64(rng.IntN(10))}
+	}
+	return points
+}
+
+func TestVertexKDTree(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	points := randomPoints(rng, 500)
+	kd := NewVertexKDTreeFromMesh(IndexedMesh{Vertices: points})
+
+	for range 200 {
+		p := r3.Vec{X: rng.Float64()*12 - 1, Y: rng.Float64()*12 - 1, Z: rng.Float64()*12 - 1}
+
+		// Brute-force nearest, ties favoring the lower index
+		wantIndex, wantDist := -1, math.Inf(1)
+		for i, v := range points {
+			if d := math.Sqrt(r3.Norm2(r3.Sub(v, p))); d < wantDist {
+				wantIndex, wantDist = i, d
+			}
+		}
+		if index, dist := kd.Nearest(p); index != wantIndex || dist != wantDist {
+			t.Errorf("Nearest(%v) = %d, %v, want %d, %v", p, index, dist, wantIndex, wantDist)
+		}
+
+		r := rng.Float64() * 3
+		var want []int
+		for i, v := range points {
+			if r3.Norm2(r3.Sub(v, p)) <= r*r {
+				want = append(want, i)
+			}
+		}
+		if got := kd.WithinRadius(p, r); !slices.Equal(got, want) {
+			t.Errorf("WithinRadius(%v, %v) = %v, want %v", p, r, got, want)
+		}
+	}
+
+	// Querying a duplicated vertex finds its first copy at distance zero
+	if index, dist := kd.Nearest(points[4]); dist != 0 || points[index] != points[4] || index > 4 {
+		t.Errorf("Nearest(duplicate) = %d, %v", index, dist)
+	}
+
+	// NewVertexKDTree drops the duplicates
+	var triangles []Triangle
+	for i := 0; i+2 < len(points); i += 3 {
+		triangles = append(triangles, triangle(points[i], points[i+1], points[i+2]))
+	}
+	if got, want := len(NewVertexKDTree(triangles).Vertices()), len(uniqueVertices(triangles)); got != want {
+		t.Errorf("NewVertexKDTree: got %d vertices, want %d", got, want)
+	}
+
+	empty := NewVertexKDTree(nil)
+	if index, dist := empty.Nearest(r3.Vec{}); index != -1 || !math.IsInf(dist, 1) {
+		t.Errorf("empty Nearest = %d, %v, want -1, +Inf", index, dist)
+	}
+}
@@ -0,0 +1,54 @@
+package stl
+
+import (
+	"io"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// Mesh is a triangle mesh with methods wrapping the package's free functions
+type Mesh struct {
+	Triangles []Triangle
+}
+
+// NewMeshFromReader reads an STL file from the given io.Reader into a Mesh.
+// Supports both binary and ASCII STL formats.
+func NewMeshFromReader(r io.Reader, opts ...Option) (*Mesh, error) {
+	triangles, err := ParseSTL(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Mesh{Triangles: triangles}, nil
+}
+
+// BoundingBox returns the axis-aligned bounding box of the mesh
+func (m *Mesh) BoundingBox() *BoundingBox {
+	return BoundingBoxFromTriangles(m.Triangles)
+}
+
+// SurfaceArea returns the total surface area of the mesh
+func (m *Mesh) SurfaceArea() float64 {
+	return SurfaceArea(m.Triangles)
+}
+
+// Volume returns the enclosed volume of the mesh, as computed by MeshVolume
+func (m *Mesh) Volume() float64 {
+	return MeshVolume(m.Triangles)
+}
+
+// Centroid returns the area-weighted centroid of the mesh surface
+func (m *Mesh) Centroid() r3.Vec {
+	return Centroid(m.Triangles)
+}
+
+// Transform returns a new mesh with the 4x4 affine transform t applied, as
+// with TransformTriangles. The receiver is left unchanged. It returns an
+// error if t is not 4x4.
+func (m *Mesh) Transform(t *mat.Dense) (*Mesh, error) {
+	triangles, err := TransformTriangles(m.Triangles, t)
+	if err != nil {
+		return nil, err
+	}
+	return &Mesh{Triangles: triangles}, nil
+}
@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package stl
+
+// CalculateBoundingBoxFromFileMmap is like CalculateBoundingBoxFromFile.
+// Memory mapping is not supported on this platform, so the file is parsed
+// with the streaming parser.
+func CalculateBoundingBoxFromFileMmap(filePath string, opts ...Option) (*BoundingBox, error) {
+	return CalculateBoundingBoxFromFile(filePath, opts...)
+}
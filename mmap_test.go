@@ -0,0 +1,55 @@
+package stl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCalculateBoundingBoxFromFileMmap(t *testing.T) {
+	binaryData := binarySTL(t, strip(5))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		opts    []Option
+		wantErr error
+	}{
+		{"binary", binaryData, nil, nil},
+		{"ascii", asciiSTL(t, "strip", strip(5)), nil, nil},
+		{"trailing data", append(binarySTL(t, strip(5)), "junk"...), nil, nil},
+		{"triangle limit", binaryData, []Option{WithTriangleLimit(2)}, nil},
+		{"truncated", binaryData[:len(binaryData)-10], nil, ErrTruncated},
+		{"empty", nil, nil, ErrNotSTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "part.stl")
+			if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// Both paths agree on the box or on the kind of error
+			got, err := CalculateBoundingBoxFromFileMmap(path, tt.opts...)
+			want, streamErr := CalculateBoundingBoxFromFile(path, tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(streamErr, tt.wantErr) {
+					t.Errorf("got errors %v and %v, want %v", err, streamErr, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || streamErr != nil {
+				t.Fatalf("got errors %v and %v", err, streamErr)
+			}
+			if !got.Equal(want) || got.Partial != want.Partial {
+				t.Errorf("got box %+v, want %+v", *got, *want)
+			}
+		})
+	}
+
+	if _, err := CalculateBoundingBoxFromFileMmap(filepath.Join(t.TempDir(), "missing.stl")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: got error %v, want os.ErrNotExist", err)
+	}
+}
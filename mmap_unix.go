@@ -0,0 +1,40 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package stl
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// CalculateBoundingBoxFromFileMmap is like CalculateBoundingBoxFromFile, but
+// memory-maps the file and parses binary STL triangles directly from the mapped
+// bytes, avoiding per-triangle reads and copies. If the file cannot be mapped,
+// it falls back to the streaming parser.
+func CalculateBoundingBoxFromFileMmap(filePath string, opts ...Option) (*BoundingBox, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading file info: %w", err)
+	}
+
+	// Empty and very large files cannot be mapped
+	size := info.Size()
+	if size <= 0 || int64(int(size)) != size {
+		return CalculateBoundingBox(file, opts...)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return CalculateBoundingBox(file, opts...)
+	}
+	defer syscall.Munmap(data)
+
+	return calculateBoundingBoxBytes(data, newConfig(opts))
+}
@@ -0,0 +1,116 @@
+package stl
+
+import (
+	"iter"
+	"math"
+	"slices"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// OrientedBoundingBox is a box aligned to the principal axes of a model
+type OrientedBoundingBox struct {
+	Center r3.Vec
+	// Axes are the unit directions of the box, ordered from the axis of
+	// greatest vertex variance to the axis of least variance
+	Axes [3]r3.Vec
+	// HalfExtents holds the half-length of the box along each of Axes
+	HalfExtents [3]float64
+}
+
+// Volume returns the volume of the oriented bounding box
+func (obb *OrientedBoundingBox) Volume() float64 {
+	return 8 * obb.HalfExtents[0] * obb.HalfExtents[1] * obb.HalfExtents[2]
+}
+
+// CalculateOrientedBoundingBox computes an oriented bounding box for the given
+// triangles using principal component analysis of their distinct vertices.
+// Shared corners are counted once so that the tessellation does not skew the
+// axes. The box is usually much tighter than the axis-aligned one for rotated
+// parts, but is not guaranteed to be the minimum-volume box.
+func CalculateOrientedBoundingBox(triangles []Triangle) *OrientedBoundingBox {
+	return orientedBoundingBox(slices.Values(uniqueVertices(triangles)))
+}
+
+// orientedBoundingBox computes an oriented bounding box for the given
+// vertices using principal component analysis. An empty sequence yields a
+// zero box.
+func orientedBoundingBox(vertices iter.Seq[r3.Vec]) *OrientedBoundingBox {
+	obb := &OrientedBoundingBox{}
+
+	// Mean of the vertex cloud
+	var mean r3.Vec
+	count := 0
+	for v := range vertices {
+		mean = r3.Add(mean, v)
+		count++
+	}
+	if count == 0 {
+		return obb
+	}
+	n := float64(count)
+	mean = r3.Scale(1/n, mean)
+
+	// Covariance matrix of the vertex cloud
+	var cxx, cxy, cxz, cyy, cyz, czz float64
+	for v := range vertices {
+		d := r3.Sub(v, mean)
+		cxx += d.X * d.X
+		cxy += d.X * d.Y
+		cxz += d.X * d.Z
+		cyy += d.Y * d.Y
+		cyz += d.Y * d.Z
+		czz += d.Z * d.Z
+	}
+	cov := mat.NewSymDense(3, []float64{
+		cxx / n, cxy / n, cxz / n,
+		cxy / n, cyy / n, cyz / n,
+		cxz / n, cyz / n, czz / n,
+	})
+
+	obb.Axes = principalAxes(cov)
+
+	// Project vertices onto the axes to find the extents along each
+	var lo, hi [3]float64
+	for k := range lo {
+		lo[k], hi[k] = math.Inf(1), math.Inf(-1)
+	}
+	for v := range vertices {
+		d := r3.Sub(v, mean)
+		for k, axis := range obb.Axes {
+			p := r3.Dot(d, axis)
+			lo[k] = math.Min(lo[k], p)
+			hi[k] = math.Max(hi[k], p)
+		}
+	}
+
+	obb.Center = mean
+	for k, axis := range obb.Axes {
+		obb.Center = r3.Add(obb.Center, r3.Scale((lo[k]+hi[k])/2, axis))
+		obb.HalfExtents[k] = (hi[k] - lo[k]) / 2
+	}
+
+	return obb
+}
+
+// principalAxes returns the eigenvectors of the symmetric 3x3 matrix m ordered
+// by descending eigenvalue. The coordinate axes are returned if the
+// decomposition fails.
+func principalAxes(m mat.Symmetric) [3]r3.Vec {
+	var eig mat.EigenSym
+	if !eig.Factorize(m, true) {
+		return [3]r3.Vec{{X: 1}, {Y: 1}, {Z: 1}}
+	}
+
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+
+	// Eigenvalues are returned in ascending order
+	var axes [3]r3.Vec
+	for k := 0; k < 3; k++ {
+		col := 2 - k
+		axes[k] = r3.Unit(r3.Vec{X: vectors.At(0, col), Y: vectors.At(1, col), Z: vectors.At(2, col)})
+	}
+	return axes
+}
@@ -0,0 +1,57 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// rotateZ returns a copy of triangles rotated by angle radians about the Z axis
+func rotateZ(triangles []Triangle, angle float64) []Triangle {
+	sin, cos := math.Sincos(angle)
+	result := make([]Triangle, len(triangles))
+	for i := range triangles {
+		for j, v := range triangles[i].Vertices {
+			result[i].Vertices[j] = r3.Vec{X: v.X*cos - v.Y*sin, Y: v.X*sin + v.Y*cos, Z: v.Z}
+		}
+	}
+	RecomputeNormals(result)
+	return result
+}
+
+func TestCalculateOrientedBoundingBox(t *testing.T) {
+	box := cuboid(r3.Vec{X: 1, Y: 2, Z: 3})
+	wantHalf := [3]float64{1.5, 1, 0.5}
+	wantAxes := [3]r3.Vec{{Z: 1}, {Y: 1}, {X: 1}}
+
+	// Axis aligned, the OBB is the AABB
+	obb := CalculateOrientedBoundingBox(box)
+	aabb := BoundingBoxFromTriangles(box)
+	if r3.Norm(r3.Sub(obb.Center, aabb.Center)) > 1e-9 {
+		t.Errorf("aligned: center = %v, want %v", obb.Center, aabb.Center)
+	}
+	if math.Abs(obb.Volume()-float64(aabb.Volume())) > 1e-9 {
+		t.Errorf("aligned: volume = %v, want %v", obb.Volume(), aabb.Volume())
+	}
+	for k := range obb.Axes {
+		if math.Abs(math.Abs(r3.Dot(obb.Axes[k], wantAxes[k]))-1) > 1e-9 {
+			t.Errorf("aligned: axis %d = %v, want ±%v", k, obb.Axes[k], wantAxes[k])
+		}
+		if math.Abs(obb.HalfExtents[k]-wantHalf[k]) > 1e-9 {
+			t.Errorf("aligned: half extent %d = %v, want %v", k, obb.HalfExtents[k], wantHalf[k])
+		}
+	}
+
+	// Rotated, the OBB keeps the original extents and beats the AABB
+	rotated := rotateZ(box, math.Pi/4)
+	obb = CalculateOrientedBoundingBox(rotated)
+	for k := range obb.HalfExtents {
+		if math.Abs(obb.HalfExtents[k]-wantHalf[k]) > 1e-9 {
+			t.Errorf("rotated: half extent %d = %v, want %v", k, obb.HalfExtents[k], wantHalf[k])
+		}
+	}
+	if aabb := BoundingBoxFromTriangles(rotated); obb.Volume() >= float64(aabb.Volume()) {
+		t.Errorf("rotated: OBB volume %v is not below AABB volume %v", obb.Volume(), aabb.Volume())
+	}
+}
@@ -0,0 +1,370 @@
+package stl
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// Option configures how an STL file is parsed
+type Option func(*config)
+
+// config holds the settings applied by Option values
+type config struct {
+	ctx          context.Context
+	format       Format
+	maxTriangles int
+	validateSize bool
+	scale        float64
+	progress     func(done, total int)
+	parallel     bool
+	// quantize is 10^decimals for WithQuantize, or 0 if disabled
+	quantize    float64
+	skipInvalid bool
+	// warnings collects the facets skipped by WithSkipInvalid, if non-nil
+	warnings *[]Warning
+	// byteOrder is the byte order of binary files, or nil for little-endian
+	byteOrder        binary.ByteOrder
+	detectEndianness bool
+	triangleLimit    int
+	// partial is set once parsing stops early because of triangleLimit
+	partial         bool
+	rejectNonFinite bool
+	skipNonFinite   bool
+	// consumed, if non-nil, counts the bytes the parser consumes once the
+	// format is known
+	consumed *countingReader
+}
+
+// newConfig returns a config with the given options applied
+func newConfig(opts []Option) *config {
+	cfg := &config{ctx: context.Background(), scale: 1, rejectNonFinite: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithFormat forces the file to be parsed as the given format instead of
+// detecting it automatically. FormatUnknown restores automatic detection.
+func WithFormat(format Format) Option {
+	return func(c *config) {
+		c.format = format
+	}
+}
+
+// WithMaxTriangles limits the number of triangles that will be parsed,
+// bounding memory usage on untrusted input. Files declaring or containing more
+// triangles return an error. A value of 0 or less means no limit.
+func WithMaxTriangles(n int) Option {
+	return func(c *config) {
+		c.maxTriangles = n
+	}
+}
+
+// WithTriangleLimit stops parsing after the first n triangles, e.g. to compute
+// an approximate box for a preview of an enormous file. Unlike
+// WithMaxTriangles, exceeding the limit is not an error: the result covers
+// the triangles read and, for bounding boxes, has Partial set. Binary files
+// declaring at most n triangles are parsed fully and are not partial. ASCII
+// files do not declare a count, so parsing stops right after the n-th
+// triangle and the result is marked partial even if it was the last one.
+// A value of 0 or less means no limit.
+func WithTriangleLimit(n int) Option {
+	return func(c *config) {
+		c.triangleLimit = n
+	}
+}
+
+// WithValidateSize checks, for seekable readers, that the size of a binary STL
+// file matches the triangle count declared in its header before parsing.
+// Without it, bytes after the last declared triangle are ignored.
+// Non-seekable readers skip the check.
+func WithValidateSize(validate bool) Option {
+	return func(c *config) {
+		c.validateSize = validate
+	}
+}
+
+// WithScale multiplies every parsed vertex by factor before it is stored or
+// used to update a bounding box. Scaling happens in double precision during
+// parsing, so the center and volume of the resulting box reflect the scaled
+// geometry. The factor should be positive; normals are left unchanged.
+func WithScale(factor float64) Option {
+	return func(c *config) {
+		c.scale = factor
+	}
+}
+
+// WithQuantize rounds every parsed vertex coordinate to the given number of
+// decimal places, after any WithScale factor is applied. This removes
+// floating-point noise such as 1.0000001 so that welding and bounding boxes
+// are reproducible across exporters. Normals are not rounded.
+func WithQuantize(decimals int) Option {
+	return func(c *config) {
+		c.quantize = math.Pow(10, float64(decimals))
+	}
+}
+
+// WithSkipInvalid makes the ASCII parser skip malformed facets, such as those
+// with an unparsable or missing vertex, instead of returning ErrInvalidVertex.
+// Use CalculateBoundingBoxWithWarnings to find out which facets were skipped.
+// Binary files are unaffected.
+func WithSkipInvalid(skip bool) Option {
+	return func(c *config) {
+		c.skipInvalid = skip
+	}
+}
+
+// WithEndianness forces binary STL files to be decoded in the given byte
+// order. The STL format is little-endian, but some legacy tools write
+// big-endian files. Passing nil restores the default.
+func WithEndianness(order binary.ByteOrder) Option {
+	return func(c *config) {
+		c.byteOrder = order
+	}
+}
+
+// WithDetectEndianness decodes binary STL files as big-endian when the
+// little-endian triangle count does not match the file size but the
+// big-endian count does. Detection requires a seekable reader and is ignored
+// if WithEndianness is also given.
+func WithDetectEndianness(detect bool) Option {
+	return func(c *config) {
+		c.detectEndianness = detect
+	}
+}
+
+// WithRejectNonFinite controls whether a vertex with a NaN or infinite
+// coordinate, as written by some broken exporters, fails parsing with
+// ErrNonFiniteVertex naming the triangle. This is the default; passing false
+// lets such vertices through, where they distort bounding boxes.
+func WithRejectNonFinite(reject bool) Option {
+	return func(c *config) {
+		c.rejectNonFinite = reject
+	}
+}
+
+// WithSkipNonFinite drops triangles with a NaN or infinite vertex coordinate
+// instead of returning ErrNonFiniteVertex, taking precedence over
+// WithRejectNonFinite. Skipped triangles do not count towards
+// WithTriangleLimit, and a file whose triangles are all skipped returns
+// ErrEmptyMesh.
+func WithSkipNonFinite(skip bool) Option {
+	return func(c *config) {
+		c.skipNonFinite = skip
+	}
+}
+
+// WithProgress registers fn to be called periodically while parsing with the
+// number of triangles parsed so far and the total declared by the file.
+// ASCII files do not declare a total, so -1 is passed instead.
+// fn is always called once parsing completes successfully.
+func WithProgress(fn func(done, total int)) Option {
+	return func(c *config) {
+		c.progress = fn
+	}
+}
+
+// ParseOptions controls how an STL file is parsed
+type ParseOptions struct {
+	// ValidateSize checks, for seekable readers, that the size of a binary STL
+	// file matches the triangle count declared in its header before parsing.
+	// Non-seekable readers skip the check.
+	ValidateSize bool
+}
+
+// CalculateBoundingBoxWithOptions reads an STL file from the given io.Reader
+// and returns its bounding box, parsing it according to opts and any
+// additional Option values.
+func CalculateBoundingBoxWithOptions(r io.Reader, opts ParseOptions, extra ...Option) (*BoundingBox, error) {
+	return CalculateBoundingBox(r, append([]Option{WithValidateSize(opts.ValidateSize)}, extra...)...)
+}
+
+// wrap returns a visitor that applies the per-triangle processing configured
+// in c before passing triangles on to v
+func (c *config) wrap(v *visitor) *visitor {
+	return c.wrapFrom(v, 0)
+}
+
+// wrapFrom is like wrap for a visitor whose first triangle has the given index
+// in the file, so errors name the right triangle
+func (c *config) wrapFrom(v *visitor, start int) *visitor {
+	checkFinite := c.rejectNonFinite || c.skipNonFinite
+	if c.scale == 1 && c.quantize == 0 && c.triangleLimit <= 0 && !checkFinite {
+		return v
+	}
+
+	wrapped := *v
+	index, accepted := start-1, 0
+	wrapped.triangle = func(t Triangle) error {
+		index++
+		for i := range t.Vertices {
+			if c.scale != 1 {
+				t.Vertices[i] = r3.Scale(c.scale, t.Vertices[i])
+			}
+			if c.quantize != 0 {
+				t.Vertices[i] = quantizeVec(t.Vertices[i], c.quantize)
+			}
+		}
+
+		if checkFinite {
+			if p, ok := nonFiniteVertex(t.Vertices); ok {
+				if c.skipNonFinite {
+					return errSkipTriangle
+				}
+				return fmt.Errorf("%w: triangle %d has vertex (%g, %g, %g)", ErrNonFiniteVertex, index, p.X, p.Y, p.Z)
+			}
+		}
+		accepted++
+		if err := v.triangle(t); err != nil {
+			return err
+		}
+		// Stop as soon as the limit is reached rather than decoding another
+		// triangle to find out whether any are left
+		if c.triangleLimit > 0 && accepted == c.triangleLimit {
+			return errTriangleLimit
+		}
+		return nil
+	}
+	return &wrapped
+}
+
+// nonFiniteVertex returns the first of vertices with a NaN or infinite
+// coordinate, if any
+func nonFiniteVertex(vertices [3]r3.Vec) (r3.Vec, bool) {
+	for _, p := range vertices {
+		if !isFinite(p.X) || !isFinite(p.Y) || !isFinite(p.Z) {
+			return p, true
+		}
+	}
+	return r3.Vec{}, false
+}
+
+// isFinite reports whether x is neither NaN nor infinite
+func isFinite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// quantizeVec rounds each coordinate of p to the nearest multiple of 1/factor
+func quantizeVec(p r3.Vec, factor float64) r3.Vec {
+	return r3.Vec{
+		X: math.Round(p.X*factor) / factor,
+		Y: math.Round(p.Y*factor) / factor,
+		Z: math.Round(p.Z*factor) / factor,
+	}
+}
+
+// addWarning records a skipped facet, if warnings are being collected
+func (c *config) addWarning(w Warning) {
+	if c.warnings != nil {
+		*c.warnings = append(*c.warnings, w)
+	}
+}
+
+// binaryOrder returns the byte order of a binary STL file of the given size
+// (-1 if unknown) whose header count decodes as count in little-endian, along
+// with the count decoded in that order
+func (c *config) binaryOrder(count uint32, size int64) (binary.ByteOrder, uint32) {
+	order := c.byteOrder
+	if order == nil {
+		order = binary.LittleEndian
+		swapped := bits.ReverseBytes32(count)
+		if c.detectEndianness && size >= 0 && binaryFileSize(count) != size && binaryFileSize(swapped) == size {
+			order = binary.BigEndian
+		}
+	}
+
+	var raw [4]byte
+	binary.LittleEndian.PutUint32(raw[:], count)
+	return order, order.Uint32(raw[:])
+}
+
+// errTriangleLimit stops parsing once WithTriangleLimit is reached. It is
+// returned by a wrapped visitor after the last triangle within the limit has
+// been passed on.
+var errTriangleLimit = errors.New("triangle limit reached")
+
+// errSkipTriangle is returned by a wrapped visitor for a triangle dropped by
+// WithSkipNonFinite, so parsers do not count it as read
+var errSkipTriangle = errors.New("triangle skipped")
+
+// visitTriangle passes t to v, reporting whether it was accepted rather than
+// dropped by the processing configured in wrap
+func visitTriangle(v *visitor, t Triangle) (bool, error) {
+	if err := v.triangle(t); err != nil {
+		if err == errSkipTriangle {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// allSkipped returns the error for a binary file declaring total triangles
+// that were all dropped by WithSkipNonFinite
+func allSkipped(total int) error {
+	return fmt.Errorf("%w: all %d triangles have non-finite vertices", ErrEmptyMesh, total)
+}
+
+// limitReached converts the error that stops parsing at the triangle limit
+// into success, recording that the result is partial
+func (c *config) limitReached(err error) error {
+	if err == errTriangleLimit {
+		c.partial = true
+		return nil
+	}
+	return err
+}
+
+// reportProgress calls the progress callback, if any
+func (c *config) reportProgress(done, total int) {
+	if c.progress != nil {
+		c.progress(done, total)
+	}
+}
+
+// exceedsMaxTriangles reports whether n triangles is over the configured limit
+func (c *config) exceedsMaxTriangles(n int) bool {
+	return c.maxTriangles > 0 && n > c.maxTriangles
+}
+
+// expectedSize returns the remaining size of r when it is needed to validate
+// the size or detect the byte order of a binary file and r is seekable, or -1
+// otherwise
+func (c *config) expectedSize(r io.Reader) (int64, error) {
+	if !c.validateSize && !c.detectEndianness {
+		return -1, nil
+	}
+	return remainingSize(r)
+}
+
+// remainingSize returns the number of bytes between the current position of r
+// and its end, or -1 if r is not seekable
+func remainingSize(r io.Reader) (int64, error) {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return -1, nil
+	}
+
+	current, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		// Not every io.Seeker supports seeking (e.g. pipes); skip the check
+		return -1, nil
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1, nil
+	}
+	if _, err := seeker.Seek(current, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("error seeking file: %w", err)
+	}
+
+	return end - current, nil
+}
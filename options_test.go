@@ -0,0 +1,194 @@
+package stl
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestNonFiniteVertices(t *testing.T) {
+	finite := triangle(r3.Vec{X: 0}, r3.Vec{X: 1}, r3.Vec{Y: 1})
+	nan := triangle(r3.Vec{X: math.NaN()}, r3.Vec{X: 1}, r3.Vec{Y: 1})
+	inf := triangle(r3.Vec{X: 0}, r3.Vec{X: 1}, r3.Vec{Z: math.Inf(1)})
+
+	mixed := []Triangle{nan, finite, inf}
+	allBad := []Triangle{nan, inf}
+
+	formats := []struct {
+		name   string
+		encode func(testing.TB, []Triangle) []byte
+	}{
+		{"binary", binarySTL},
+		{"ascii", func(t testing.TB, triangles []Triangle) []byte { return asciiSTL(t, "part", triangles) }},
+	}
+
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			t.Run("reject by default", func(t *testing.T) {
+				_, err := CalculateBoundingBox(bytes.NewReader(format.encode(t, mixed)))
+				if !errors.Is(err, ErrNonFiniteVertex) {
+					t.Fatalf("got error %v, want ErrNonFiniteVertex", err)
+				}
+			})
+
+			t.Run("allow", func(t *testing.T) {
+				_, err := CalculateBoundingBox(bytes.NewReader(format.encode(t, mixed)), WithRejectNonFinite(false))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+
+			t.Run("skip", func(t *testing.T) {
+				bbox, err := CalculateBoundingBox(bytes.NewReader(format.encode(t, mixed)), WithSkipNonFinite(true))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				want := BoundingBoxFromTriangles([]Triangle{finite})
+				if !bbox.Equal(want) {
+					t.Errorf("got box %+v, want %+v", *bbox, *want)
+				}
+			})
+
+			t.Run("skip all", func(t *testing.T) {
+				data := format.encode(t, allBad)
+				for _, parallel := range []bool{false, true} {
+					_, err := CalculateBoundingBox(bytes.NewReader(data), WithSkipNonFinite(true), WithParallel(parallel))
+					if !errors.Is(err, ErrEmptyMesh) {
+						t.Errorf("parallel %v: got error %v, want ErrEmptyMesh", parallel, err)
+					}
+				}
+				if _, err := CalculateBoundingBoxFromBytes(data, WithSkipNonFinite(true)); !errors.Is(err, ErrEmptyMesh) {
+					t.Errorf("from bytes: got error %v, want ErrEmptyMesh", err)
+				}
+			})
+
+			t.Run("skip before limit", func(t *testing.T) {
+				bbox, err := CalculateBoundingBox(bytes.NewReader(format.encode(t, mixed)), WithSkipNonFinite(true), WithTriangleLimit(1))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if bbox.IsEmpty() {
+					t.Errorf("got an empty box, want the finite triangle's box")
+				}
+			})
+		})
+	}
+}
+
+func TestWithProgress(t *testing.T) {
+	triangles := strip(3*checkInterval + 5)
+	n := len(triangles)
+	binaryData := binarySTL(t, triangles)
+
+	type call struct{ done, total int }
+	record := func(calls *[]call) Option {
+		return WithProgress(func(done, total int) {
+			*calls = append(*calls, call{done, total})
+		})
+	}
+
+	tests := []struct {
+		name  string
+		run   func(opt Option) error
+		total int
+	}{
+		{"binary", func(opt Option) error {
+			_, err := CalculateBoundingBox(bytes.NewReader(binaryData), opt)
+			return err
+		}, n},
+		{"binary bytes", func(opt Option) error {
+			_, err := CalculateBoundingBoxFromBytes(binaryData, opt)
+			return err
+		}, n},
+		{"ascii", func(opt Option) error {
+			_, err := CalculateBoundingBox(bytes.NewReader(asciiSTL(t, "strip", triangles)), opt)
+			return err
+		}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []call
+			if err := tt.run(record(&calls)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(calls) < 2 {
+				t.Fatalf("got %d progress calls, want periodic reports", len(calls))
+			}
+
+			for i, c := range calls {
+				if c.total != tt.total {
+					t.Errorf("call %d: total = %d, want %d", i, c.total, tt.total)
+				}
+				if i > 0 && c.done < calls[i-1].done {
+					t.Errorf("call %d: done went from %d to %d", i, calls[i-1].done, c.done)
+				}
+			}
+			if last := calls[len(calls)-1]; last.done != n {
+				t.Errorf("last call: done = %d, want %d", last.done, n)
+			}
+		})
+	}
+}
+
+func TestWithTriangleLimit(t *testing.T) {
+	triangles := strip(5)
+	binaryData := binarySTL(t, triangles)
+	asciiData := asciiSTL(t, "strip", triangles)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		limit   int
+		want    int
+		partial bool
+	}{
+		{"binary below count", binaryData, 2, 2, true},
+		// The declared count shows nothing was left out
+		{"binary at count", binaryData, 5, 5, false},
+		{"binary above count", binaryData, 7, 5, false},
+		{"ascii below count", asciiData, 2, 2, true},
+		// Without a declared count, stopping at the limit cannot tell
+		// whether more triangles follow
+		{"ascii at count", asciiData, 5, 5, true},
+		{"ascii above count", asciiData, 7, 5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := BoundingBoxFromTriangles(triangles[:tt.want])
+			want.Partial = tt.partial
+
+			bbox, err := CalculateBoundingBox(bytes.NewReader(tt.data), WithTriangleLimit(tt.limit))
+			if err != nil {
+				t.Fatalf("CalculateBoundingBox: %v", err)
+			}
+			if !bbox.Equal(want) {
+				t.Errorf("got box %+v, want %+v", *bbox, *want)
+			}
+
+			bbox, err = CalculateBoundingBoxFromBytes(tt.data, WithTriangleLimit(tt.limit))
+			if err != nil {
+				t.Fatalf("CalculateBoundingBoxFromBytes: %v", err)
+			}
+			if !bbox.Equal(want) {
+				t.Errorf("from bytes: got box %+v, want %+v", *bbox, *want)
+			}
+
+			count := 0
+			err = ForEachTriangle(bytes.NewReader(tt.data), func(Triangle) error {
+				count++
+				return nil
+			}, WithTriangleLimit(tt.limit))
+			if err != nil {
+				t.Fatalf("ForEachTriangle: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("ForEachTriangle visited %d triangles, want %d", count, tt.want)
+			}
+		})
+	}
+}
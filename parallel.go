@@ -0,0 +1,160 @@
+package stl
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// WithParallel enables parallel bounding box computation for binary STL files
+// read from an input that implements both io.ReaderAt and io.Seeker, such as
+// an *os.File. The triangle range is split into chunks processed concurrently
+// across runtime.NumCPU() goroutines and the partial boxes are merged, giving
+// the same result as the serial path. Other inputs and ASCII files are parsed
+// serially. Progress is only reported once parsing completes.
+func WithParallel(parallel bool) Option {
+	return func(c *config) {
+		c.parallel = parallel
+	}
+}
+
+// calculateParallel computes the bounding box of a binary STL file in
+// parallel. It returns false if r does not support random access or does not
+// hold a binary file, in which case r is left unread.
+func calculateParallel(r io.Reader, cfg *config) (*BoundingBox, bool, error) {
+	// Stopping early is inherently sequential
+	if cfg.triangleLimit > 0 {
+		return nil, false, nil
+	}
+
+	ra, ok := r.(io.ReaderAt)
+	if !ok {
+		return nil, false, nil
+	}
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return nil, false, nil
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, false, nil
+	}
+	size, err := remainingSize(r)
+	if err != nil || size < 0 {
+		return nil, false, err
+	}
+
+	if cfg.format == FormatUnknown {
+		window := make([]byte, detectWindow)
+		n, err := ra.ReadAt(window, start)
+		if err != nil && err != io.EOF {
+			return nil, true, fmt.Errorf("error reading header: %w", err)
+		}
+		format, err := detectedFormat(window[:n], size)
+		if err != nil {
+			return nil, true, err
+		}
+		if format == FormatASCII {
+			return nil, false, nil
+		}
+	} else if cfg.format != FormatBinary {
+		return nil, false, nil
+	}
+
+	numTriangles, err := readBinaryHeader(io.NewSectionReader(ra, start, 84))
+	if err != nil {
+		return nil, true, err
+	}
+	order, numTriangles := cfg.binaryOrder(numTriangles, size)
+	if err := checkBinaryHeader(cfg, numTriangles, size); err != nil {
+		return nil, true, err
+	}
+
+	total := int(numTriangles)
+	workers := runtime.NumCPU()
+	chunk := (total + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	boxes := make([]*BoundingBox, workers)
+	accepted := make([]int, workers)
+	errs := make([]error, workers)
+	for w := 0; w < workers; w++ {
+		lo, hi := w*chunk, min((w+1)*chunk, total)
+		if lo >= hi {
+			break
+		}
+
+		wg.Add(1)
+		go func(w, lo, hi int) {
+			defer wg.Done()
+			boxes[w], accepted[w], errs[w] = boundingBoxOfRange(ra, start, lo, hi, order, cfg)
+		}(w, lo, hi)
+	}
+	wg.Wait()
+
+	// Report the error from the earliest chunk, as the serial path would
+	for _, err := range errs {
+		if err != nil {
+			return nil, true, err
+		}
+	}
+	read := 0
+	for _, n := range accepted {
+		read += n
+	}
+	if read == 0 {
+		return nil, true, allSkipped(total)
+	}
+
+	bbox := UnionAll(boxes...)
+
+	// Leave r positioned after the triangles, as the serial path would
+	if _, err := seeker.Seek(start+binaryFileSize(numTriangles), io.SeekStart); err != nil {
+		return nil, true, fmt.Errorf("error seeking file: %w", err)
+	}
+
+	cfg.reportProgress(total, total)
+	return bbox, true, nil
+}
+
+// boundingBoxOfRange computes the bounding box of triangles [lo, hi) of the
+// binary STL file in the given byte order starting at offset start of ra,
+// along with the number of triangles not dropped by WithSkipNonFinite
+func boundingBoxOfRange(ra io.ReaderAt, start int64, lo, hi int, order binary.ByteOrder, cfg *config) (*BoundingBox, int, error) {
+	offset := start + binaryFileSize(uint32(lo))
+	br := bufio.NewReader(io.NewSectionReader(ra, offset, int64(hi-lo)*50))
+
+	bbox := newBoundingBox()
+	v := cfg.wrapFrom(&visitor{triangle: func(t Triangle) error {
+		updateBoundingBox(bbox, t.Vertices[:])
+		return nil
+	}}, lo)
+
+	record := make([]byte, 50)
+	accepted := 0
+	for i := lo; i < hi; i++ {
+		if (i-lo)%checkInterval == 0 {
+			if err := cfg.ctx.Err(); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		triangle, _, err := readBinaryTriangle(br, record, i, order)
+		if err != nil {
+			return nil, 0, err
+		}
+		ok, err := visitTriangle(v, triangle)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			accepted++
+		}
+	}
+
+	return bbox, accepted, nil
+}
@@ -0,0 +1,63 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParallelMatchesSerial(t *testing.T) {
+	// Several checkInterval blocks, with a remainder so chunks are uneven
+	triangles := strip(3*checkInterval + 17)
+	data := binarySTL(t, triangles)
+
+	path := filepath.Join(t.TempDir(), "strip.stl")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range [][]Option{
+		nil,
+		{WithScale(2.5), WithQuantize(1)},
+	} {
+		want, err := CalculateBoundingBox(bytes.NewReader(data), opts...)
+		if err != nil {
+			t.Fatalf("serial: %v", err)
+		}
+
+		got, ok, err := calculateParallel(bytes.NewReader(data), newConfig(opts))
+		if !ok || err != nil {
+			t.Fatalf("calculateParallel returned %v, %v; want the parallel path to succeed", ok, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("calculateParallel: got %+v, want %+v", *got, *want)
+		}
+
+		got, err = CalculateBoundingBoxFromFile(path, append(opts, WithParallel(true))...)
+		if err != nil {
+			t.Fatalf("parallel from file: %v", err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("parallel from file: got %+v, want %+v", *got, *want)
+		}
+	}
+
+	// Merging chunk boxes must agree however the range is split
+	want := BoundingBoxFromTriangles(triangles)
+	for _, chunks := range []int{1, 2, 3, 7} {
+		size := (len(triangles) + chunks - 1) / chunks
+		var boxes []*BoundingBox
+		for lo := 0; lo < len(triangles); lo += size {
+			box, _, err := boundingBoxOfRange(bytes.NewReader(data), 0, lo, min(lo+size, len(triangles)), binary.LittleEndian, newConfig(nil))
+			if err != nil {
+				t.Fatalf("%d chunks: %v", chunks, err)
+			}
+			boxes = append(boxes, box)
+		}
+		if got := UnionAll(boxes...); !got.Equal(want) {
+			t.Errorf("%d chunks: got %+v, want %+v", chunks, *got, *want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package stl
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ParseTriangleAt reads the triangle at the given index of a binary STL file
+// without scanning the triangles before it, by reading the 50-byte record at
+// offset 84 + index*50. The index is checked against the count declared in
+// the header. ASCII files are not supported and return an error.
+func ParseTriangleAt(r io.ReaderAt, index int) (Triangle, error) {
+	window := make([]byte, detectWindow)
+	n, err := r.ReadAt(window, 0)
+	if err != nil && err != io.EOF {
+		return Triangle{}, fmt.Errorf("error reading header: %w", err)
+	}
+	format, err := detectedFormat(window[:n], -1)
+	if err != nil {
+		return Triangle{}, err
+	}
+	if format == FormatASCII {
+		return Triangle{}, errors.New("random access is not supported for ASCII STL files")
+	}
+
+	numTriangles := binary.LittleEndian.Uint32(window[80:84])
+	if index < 0 || int64(index) >= int64(numTriangles) {
+		return Triangle{}, fmt.Errorf("triangle index %d out of range: file declares %d triangles", index, numTriangles)
+	}
+
+	record := make([]byte, 50)
+	n, err = r.ReadAt(record, binaryFileSize(uint32(index)))
+	if n < len(record) {
+		if err == nil || err == io.EOF {
+			err = truncationError(n)
+		}
+		return Triangle{}, readError(fmt.Sprintf("error reading triangle %d", index), err)
+	}
+
+	return decodeBinaryTriangle(record, binary.LittleEndian), nil
+}
@@ -0,0 +1,43 @@
+package stl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestParseTriangleAt(t *testing.T) {
+	data := binarySTL(t, strip(5))
+	want, err := ParseSTL(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseSTL: %v", err)
+	}
+
+	r := bytes.NewReader(data)
+	for _, index := range []int{0, 2, len(want) - 1} {
+		got, err := ParseTriangleAt(r, index)
+		if err != nil {
+			t.Fatalf("ParseTriangleAt(%d): %v", index, err)
+		}
+		if got != want[index] {
+			t.Errorf("ParseTriangleAt(%d) = %v, want %v", index, got, want[index])
+		}
+	}
+
+	for _, index := range []int{-1, len(want)} {
+		if _, err := ParseTriangleAt(r, index); err == nil {
+			t.Errorf("ParseTriangleAt(%d): got no error", index)
+		}
+	}
+
+	// The header declares more triangles than the file holds
+	truncated := bytes.NewReader(data[:len(data)-20])
+	if _, err := ParseTriangleAt(truncated, len(want)-1); !errors.Is(err, ErrTruncated) {
+		t.Errorf("truncated: got error %v, want ErrTruncated", err)
+	}
+
+	ascii := bytes.NewReader(asciiSTL(t, "strip", strip(5)))
+	if _, err := ParseTriangleAt(ascii, 0); err == nil {
+		t.Errorf("ASCII: got no error")
+	}
+}
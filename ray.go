@@ -0,0 +1,156 @@
+package stl
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// rayEpsilon is the tolerance used by ray-triangle intersection tests. The
+// parallel check scales it by the lengths of the ray direction and the
+// triangle's edges, so it behaves the same for models in meters or microns.
+const rayEpsilon = 1e-12
+
+// rayHit describes where a ray crosses a triangle
+type rayHit struct {
+	// t is the distance along the ray, in units of its direction vector
+	t float64
+	// u and v are the barycentric coordinates of the hit point
+	u, v float64
+}
+
+// onEdge reports whether the hit lies on, or within tol of, an edge or vertex
+// of the triangle
+func (h rayHit) onEdge(tol float64) bool {
+	return h.u < tol || h.v < tol || h.u+h.v > 1-tol
+}
+
+// intersectTriangle returns where the ray from origin along dir crosses the
+// triangle with the given vertices, using the Möller–Trumbore algorithm. Rays
+// parallel to the triangle never hit it, and only hits with t > rayEpsilon
+// count.
+func intersectTriangle(origin, dir r3.Vec, v [3]r3.Vec) (rayHit, bool) {
+	edge1 := r3.Sub(v[1], v[0])
+	edge2 := r3.Sub(v[2], v[0])
+
+	p := r3.Cross(dir, edge2)
+	det := r3.Dot(edge1, p)
+	if isParallel(det, dir, edge1, edge2) {
+		return rayHit{}, false
+	}
+	inv := 1 / det
+
+	s := r3.Sub(origin, v[0])
+	u := r3.Dot(s, p) * inv
+	if u < 0 || u > 1 {
+		return rayHit{}, false
+	}
+
+	q := r3.Cross(s, edge1)
+	w := r3.Dot(dir, q) * inv
+	if w < 0 || u+w > 1 {
+		return rayHit{}, false
+	}
+
+	t := r3.Dot(edge2, q) * inv
+	if t <= rayEpsilon {
+		return rayHit{}, false
+	}
+	return rayHit{t: t, u: u, v: w}, true
+}
+
+// isParallel reports whether a ray along dir is parallel to the triangle with
+// edges edge1 and edge2, given the Möller–Trumbore determinant det. The
+// determinant grows with each of the three lengths, so the tolerance does too;
+// degenerate triangles and a zero dir count as parallel.
+func isParallel(det float64, dir, edge1, edge2 r3.Vec) bool {
+	return math.Abs(det) <= rayEpsilon*r3.Norm(dir)*r3.Norm(edge1)*r3.Norm(edge2)
+}
+
+// grazesTriangle reports whether the ray from origin along dir is parallel to
+// the triangle with vertices v and lies within tol of its plane, relative to
+// the triangle's size. Such a ray may run across the triangle without
+// intersectTriangle reporting a hit.
+func grazesTriangle(origin, dir r3.Vec, v [3]r3.Vec, tol float64) bool {
+	edge1 := r3.Sub(v[1], v[0])
+	edge2 := r3.Sub(v[2], v[0])
+	if !isParallel(r3.Dot(edge1, r3.Cross(dir, edge2)), dir, edge1, edge2) {
+		return false
+	}
+
+	normal := r3.Cross(edge1, edge2)
+	if normal == (r3.Vec{}) {
+		return false
+	}
+	size := max(r3.Norm(edge1), r3.Norm(edge2))
+	return math.Abs(r3.Dot(r3.Sub(origin, v[0]), normal)) <= tol*size*r3.Norm(normal)
+}
+
+// RayIntersect returns the nearest triangle hit by the ray from origin along
+// dir, using the Möller–Trumbore algorithm against every triangle. t is the
+// distance to the hit in units of dir, so origin + t*dir is the hit point,
+// and index is the triangle's position in triangles. Only hits in front of the
+// origin count; triangles parallel to the ray are never hit. If nothing is
+// hit, hit is false and index is -1.
+func RayIntersect(triangles []Triangle, origin, dir r3.Vec) (hit bool, t float64, index int) {
+	index = -1
+	for i := range triangles {
+		h, ok := intersectTriangle(origin, dir, triangles[i].Vertices)
+		if ok && (!hit || h.t < t) {
+			hit, t, index = true, h.t, i
+		}
+	}
+	return hit, t, index
+}
+
+// parityDirections are the ray directions tried by PointInMesh. The first is
+// +X; the rest are slightly perturbed, irrational-looking directions used when
+// a ray grazes an edge or vertex.
+var parityDirections = []r3.Vec{
+	{X: 1},
+	{X: 1, Y: 0.0137, Z: 0.0071},
+	{X: 1, Y: -0.0093, Z: 0.0159},
+	{X: 1, Y: 0.0211, Z: -0.0127},
+	{X: 1, Y: -0.0181, Z: -0.0233},
+}
+
+// PointInMesh reports whether p lies inside the mesh, by casting a ray from p
+// along +X and counting how many triangles it crosses: an odd count means p is
+// inside. If the ray grazes an edge or vertex, where a crossing could be
+// counted twice or not at all, or runs along the plane of a face, it is cast
+// again in a slightly perturbed direction. The mesh must be watertight for the result to be meaningful;
+// points lying on the surface may be reported either way.
+func PointInMesh(triangles []Triangle, p r3.Vec) bool {
+	inside := false
+	for _, dir := range parityDirections {
+		var ambiguous bool
+		inside, ambiguous = rayParity(triangles, p, dir)
+		if !ambiguous {
+			break
+		}
+	}
+	return inside
+}
+
+// rayParity casts a ray from origin along dir and reports whether it crosses
+// an odd number of triangles, and whether any crossing was too close to an
+// edge or vertex, or the ray too close to the plane of a face it is parallel
+// to, to be trusted
+func rayParity(triangles []Triangle, origin, dir r3.Vec) (odd, ambiguous bool) {
+	const edgeTolerance = 1e-9
+
+	for i := range triangles {
+		hit, ok := intersectTriangle(origin, dir, triangles[i].Vertices)
+		if !ok {
+			if grazesTriangle(origin, dir, triangles[i].Vertices, edgeTolerance) {
+				ambiguous = true
+			}
+			continue
+		}
+		if hit.onEdge(edgeTolerance) {
+			ambiguous = true
+		}
+		odd = !odd
+	}
+	return odd, ambiguous
+}
@@ -0,0 +1,87 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestRayIntersect(t *testing.T) {
+	cube := unitCube()
+
+	tests := []struct {
+		name        string
+		origin, dir r3.Vec
+		hit         bool
+		t           float64
+		index       int
+	}{
+		// Enters through the bottom face, in the triangle covering y >= x
+		{"bottom face", r3.Vec{X: 0.3, Y: 0.4, Z: -5}, r3.Vec{Z: 1}, true, 5, 0},
+		// t is measured in units of dir
+		{"scaled direction", r3.Vec{X: 0.3, Y: 0.4, Z: -5}, r3.Vec{Z: 2}, true, 2.5, 0},
+		// Starts inside, so only the top face is ahead
+		{"from inside", r3.Vec{X: 0.3, Y: 0.4, Z: 0.5}, r3.Vec{Z: 1}, true, 0.5, 3},
+		{"miss", r3.Vec{X: 2, Y: 2, Z: -5}, r3.Vec{Z: 1}, false, 0, -1},
+		// The cube lies behind the origin
+		{"behind", r3.Vec{X: 0.3, Y: 0.4, Z: 5}, r3.Vec{Z: 1}, false, 0, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, dist, index := RayIntersect(cube, tt.origin, tt.dir)
+			if hit != tt.hit || math.Abs(dist-tt.t) > 1e-12 || index != tt.index {
+				t.Errorf("RayIntersect = %v, %v, %d, want %v, %v, %d", hit, dist, index, tt.hit, tt.t, tt.index)
+			}
+		})
+	}
+}
+
+func TestRayIntersectScale(t *testing.T) {
+	// The parallel check scales with the mesh, so a micron-sized cube is hit
+	// just like a unit one
+	for _, size := range []float64{1e-7, 1, 1e7} {
+		cube := cuboid(r3.Vec{X: size, Y: size, Z: size})
+		origin := r3.Vec{X: 0.3 * size, Y: 0.4 * size, Z: -size}
+		hit, dist, index := RayIntersect(cube, origin, r3.Vec{Z: size})
+		if !hit || math.Abs(dist-1) > 1e-9 || index != 0 {
+			t.Errorf("size %v: RayIntersect = %v, %v, %d, want true, 1, 0", size, hit, dist, index)
+		}
+		if !PointInMesh(cube, r3.Scale(0.5, r3.Vec{X: size, Y: size, Z: size})) {
+			t.Errorf("size %v: center is not inside", size)
+		}
+	}
+}
+
+func TestPointInMeshGrazing(t *testing.T) {
+	cube := unitCube()
+
+	tests := []struct {
+		name   string
+		p      r3.Vec
+		inside bool
+	}{
+		{"inside", r3.Vec{X: 0.5, Y: 0.3, Z: 0.6}, true},
+		{"outside", r3.Vec{X: 2, Y: 0.3, Z: 0.6}, false},
+		// The +X ray runs along the plane of the bottom and top faces,
+		// crossing them without hitting either
+		{"in bottom plane", r3.Vec{X: -1, Y: 0.3}, false},
+		{"in top plane", r3.Vec{X: -1, Y: 0.3, Z: 1}, false},
+		// Parallel to the bottom face but just inside it
+		{"above bottom plane", r3.Vec{X: 0.5, Y: 0.3, Z: 1e-6}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PointInMesh(cube, tt.p); got != tt.inside {
+				t.Errorf("PointInMesh(%v) = %v, want %v", tt.p, got, tt.inside)
+			}
+		})
+	}
+
+	// A ray in a face plane is not trusted, so the parity is recomputed
+	if _, ambiguous := rayParity(cube, r3.Vec{X: -1, Y: 0.3}, r3.Vec{X: 1}); !ambiguous {
+		t.Errorf("rayParity along the bottom plane is not ambiguous")
+	}
+}
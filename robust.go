@@ -0,0 +1,52 @@
+package stl
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// RobustBoundingBox returns a box spanning, on each axis independently, the
+// lowPct-th to highPct-th percentile of the vertex coordinates of the given
+// triangles, e.g. 1 and 99. A handful of stray outlier triangles in a noisy
+// scan then no longer inflate the box. By design the result does not contain
+// all of the geometry. Percentiles must be in [0, 100], with lowPct <= highPct;
+// otherwise an error is returned, as they are usually tuned by the user and a
+// swapped or out-of-range pair is easy to pass by mistake.
+func RobustBoundingBox(triangles []Triangle, lowPct, highPct float64) (*BoundingBox, error) {
+	if !(0 <= lowPct && lowPct <= highPct && highPct <= 100) {
+		return nil, fmt.Errorf("invalid percentiles %v and %v: must satisfy 0 <= low <= high <= 100", lowPct, highPct)
+	}
+
+	bbox := newBoundingBox()
+	if len(triangles) == 0 {
+		updateCenter(bbox)
+		return bbox, nil
+	}
+
+	xs := make([]float64, 0, 3*len(triangles))
+	ys := make([]float64, 0, 3*len(triangles))
+	zs := make([]float64, 0, 3*len(triangles))
+	for i := range triangles {
+		for _, v := range triangles[i].Vertices {
+			xs = append(xs, v.X)
+			ys = append(ys, v.Y)
+			zs = append(zs, v.Z)
+		}
+	}
+
+	bbox.MinX, bbox.MaxX = percentileRange(xs, lowPct, highPct)
+	bbox.MinY, bbox.MaxY = percentileRange(ys, lowPct, highPct)
+	bbox.MinZ, bbox.MaxZ = percentileRange(zs, lowPct, highPct)
+	updateCenter(bbox)
+	return bbox, nil
+}
+
+// percentileRange sorts values and returns their lowPct-th and highPct-th percentiles
+func percentileRange(values []float64, lowPct, highPct float64) (float32, float32) {
+	sort.Float64s(values)
+	lo := stat.Quantile(lowPct/100, stat.Empirical, values, nil)
+	hi := stat.Quantile(highPct/100, stat.Empirical, values, nil)
+	return float32(lo), float32(hi)
+}
@@ -0,0 +1,22 @@
+package stl
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRobustBoundingBox(t *testing.T) {
+	bbox, err := RobustBoundingBox(unitCube(), 0, 100)
+	if err != nil {
+		t.Fatalf("RobustBoundingBox: %v", err)
+	}
+	if want := BoundingBoxFromTriangles(unitCube()); !bbox.Equal(want) {
+		t.Errorf("full range: got box %+v, want %+v", *bbox, *want)
+	}
+
+	for _, pct := range [][2]float64{{-1, 50}, {50, 101}, {60, 40}, {math.NaN(), 50}} {
+		if _, err := RobustBoundingBox(unitCube(), pct[0], pct[1]); err == nil {
+			t.Errorf("RobustBoundingBox(%v, %v): got nil error", pct[0], pct[1])
+		}
+	}
+}
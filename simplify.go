@@ -0,0 +1,89 @@
+package stl
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// SimplifyByClustering returns a decimated copy of the given triangles, e.g.
+// for thumbnails and collision proxies. Vertices are grouped into cubic grid
+// cells of the given size and each group is replaced by its mean, so larger
+// cells remove more detail while the bounding box shrinks by at most about one
+// cell. Triangles left with fewer than three distinct cells are collapsed,
+// and triangles mapping to the same cells are kept once. Normals are
+// recomputed. A gridSize of 0 or less returns an unmodified copy.
+func SimplifyByClustering(triangles []Triangle, gridSize float64) []Triangle {
+	if gridSize <= 0 {
+		return append([]Triangle(nil), triangles...)
+	}
+
+	cell := func(v r3.Vec) weldKey {
+		return weldKey{
+			int64(math.Floor(v.X / gridSize)),
+			int64(math.Floor(v.Y / gridSize)),
+			int64(math.Floor(v.Z / gridSize)),
+		}
+	}
+
+	// Average the vertices falling in each cell
+	type cluster struct {
+		sum   r3.Vec
+		count int
+	}
+	clusters := make(map[weldKey]*cluster)
+	for i := range triangles {
+		for _, v := range triangles[i].Vertices {
+			key := cell(v)
+			c, ok := clusters[key]
+			if !ok {
+				c = &cluster{}
+				clusters[key] = c
+			}
+			c.sum = r3.Add(c.sum, v)
+			c.count++
+		}
+	}
+	means := make(map[weldKey]r3.Vec, len(clusters))
+	for key, c := range clusters {
+		means[key] = r3.Scale(1/float64(c.count), c.sum)
+	}
+
+	seen := make(map[[3]weldKey]struct{})
+	var simplified []Triangle
+	for i := range triangles {
+		var keys [3]weldKey
+		for j, v := range triangles[i].Vertices {
+			keys[j] = cell(v)
+		}
+		if keys[0] == keys[1] || keys[1] == keys[2] || keys[0] == keys[2] {
+			continue
+		}
+
+		sorted := keys
+		sort.Slice(sorted[:], func(a, b int) bool { return lessWeldKey(sorted[a], sorted[b]) })
+		if _, ok := seen[sorted]; ok {
+			continue
+		}
+		seen[sorted] = struct{}{}
+
+		var t Triangle
+		for j, key := range keys {
+			t.Vertices[j] = means[key]
+		}
+		t.Normal = faceNormal(t.Vertices)
+		simplified = append(simplified, t)
+	}
+	return simplified
+}
+
+// lessWeldKey orders grid cells lexicographically
+func lessWeldKey(a, b weldKey) bool {
+	for k := range a {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// grid returns an n by n grid of squares with the given spacing, each split
+// into two triangles, on a gently sloped plane
+func grid(n int, spacing float64) []Triangle {
+	point := func(i, j int) r3.Vec {
+		x, y := float64(i)*spacing, float64(j)*spacing
+		return r3.Vec{X: x, Y: y, Z: x / 4}
+	}
+	var triangles []Triangle
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			triangles = append(triangles,
+				triangle(point(i, j), point(i+1, j), point(i+1, j+1)),
+				triangle(point(i, j), point(i+1, j+1), point(i, j+1)),
+			)
+		}
+	}
+	return triangles
+}
+
+func TestSimplifyByClustering(t *testing.T) {
+	fine := grid(40, 0.1)
+	const cellSize = 0.5
+
+	simplified := SimplifyByClustering(fine, cellSize)
+	if len(simplified) == 0 || len(simplified) >= len(fine)/4 {
+		t.Errorf("got %d triangles from %d, want far fewer but not none", len(simplified), len(fine))
+	}
+	for i, tri := range simplified {
+		if tri.Area() < 1e-12 {
+			t.Errorf("triangle %d is degenerate: %v", i, tri.Vertices)
+		}
+		if math.Abs(r3.Norm(tri.Normal)-1) > 1e-9 {
+			t.Errorf("triangle %d normal %v is not recomputed", i, tri.Normal)
+		}
+	}
+
+	before, after := BoundingBoxFromTriangles(fine), BoundingBoxFromTriangles(simplified)
+	lo, hi := before.MinVec(), before.MaxVec()
+	gotLo, gotHi := after.MinVec(), after.MaxVec()
+	for _, d := range []float64{
+		gotLo.X - lo.X, gotLo.Y - lo.Y, gotLo.Z - lo.Z,
+		hi.X - gotHi.X, hi.Y - gotHi.Y, hi.Z - gotHi.Z,
+	} {
+		if d < -1e-9 || d > cellSize {
+			t.Errorf("bounding box moved from %v to %v, more than one cell", before, after)
+			break
+		}
+	}
+
+	// A cell larger than the whole mesh collapses every triangle
+	if got := SimplifyByClustering(fine, 100); len(got) != 0 {
+		t.Errorf("huge cells: got %d triangles, want 0", len(got))
+	}
+
+	if got := SimplifyByClustering(fine, 0); len(got) != len(fine) || &got[0] == &fine[0] {
+		t.Errorf("zero cell size: want an unmodified copy")
+	}
+}
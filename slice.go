@@ -0,0 +1,55 @@
+package stl
+
+import "gonum.org/v1/gonum/spatial/r3"
+
+// SliceAtZ returns the line segments where the given triangles cross the
+// horizontal plane at height z, i.e. the unchained cross-section outline.
+// Triangles lying in the plane or only touching it at a vertex contribute no
+// segment. An edge lying in the plane is reported only by a triangle extending
+// above it, so it appears once for a closed mesh.
+func SliceAtZ(triangles []Triangle, z float64) [][2]r3.Vec {
+	var segments [][2]r3.Vec
+	for i := range triangles {
+		if segment, ok := sliceTriangle(triangles[i].Vertices, z); ok {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// sliceTriangle returns the segment where the triangle with vertices v crosses
+// the plane at height z, if any
+func sliceTriangle(v [3]r3.Vec, z float64) ([2]r3.Vec, bool) {
+	var d [3]float64
+	above, below := 0, 0
+	for i := range v {
+		d[i] = v[i].Z - z
+		switch {
+		case d[i] > 0:
+			above++
+		case d[i] < 0:
+			below++
+		}
+	}
+	if above == 0 || (below == 0 && above != 1) {
+		// Without vertices on both sides, only an edge in the plane with the
+		// third vertex above it yields a segment
+		return [2]r3.Vec{}, false
+	}
+
+	var points []r3.Vec
+	for i := range v {
+		j := (i + 1) % 3
+		if d[i] == 0 {
+			points = append(points, v[i])
+		}
+		if d[i]*d[j] < 0 {
+			t := d[i] / (d[i] - d[j])
+			points = append(points, r3.Add(v[i], r3.Scale(t, r3.Sub(v[j], v[i]))))
+		}
+	}
+	if len(points) != 2 {
+		return [2]r3.Vec{}, false
+	}
+	return [2]r3.Vec{points[0], points[1]}, true
+}
@@ -0,0 +1,87 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// checkClosedLoop fails t unless segments are distinct and every endpoint is
+// shared by exactly two of them, with the given total length
+func checkClosedLoop(t *testing.T, segments [][2]r3.Vec, length float64) {
+	t.Helper()
+
+	same := func(a, b r3.Vec) bool { return r3.Norm(r3.Sub(a, b)) < 1e-12 }
+	total := 0.0
+	for i, s := range segments {
+		total += r3.Norm(r3.Sub(s[1], s[0]))
+		for _, p := range s {
+			uses := 0
+			for _, other := range segments {
+				if same(p, other[0]) || same(p, other[1]) {
+					uses++
+				}
+			}
+			if uses != 2 {
+				t.Errorf("endpoint %v of segment %d is used by %d segments, want 2", p, i, uses)
+			}
+		}
+		for j := i + 1; j < len(segments); j++ {
+			other := segments[j]
+			if (same(s[0], other[0]) && same(s[1], other[1])) || (same(s[0], other[1]) && same(s[1], other[0])) {
+				t.Errorf("segments %d and %d are duplicates: %v", i, j, s)
+			}
+		}
+	}
+	if math.Abs(total-length) > 1e-12 {
+		t.Errorf("total length = %v, want %v", total, length)
+	}
+}
+
+func TestSliceAtZ(t *testing.T) {
+	cube := unitCube()
+
+	// Midway up, each side face contributes one segment per triangle
+	segments := SliceAtZ(cube, 0.5)
+	if len(segments) != 8 {
+		t.Fatalf("cube at 0.5: got %d segments, want 8", len(segments))
+	}
+	for _, s := range segments {
+		for _, p := range s {
+			onSquare := p.X == 0 || p.X == 1 || p.Y == 0 || p.Y == 1
+			if p.Z != 0.5 || !onSquare {
+				t.Errorf("cube at 0.5: endpoint %v is not on the unit square at z = 0.5", p)
+			}
+		}
+	}
+	checkClosedLoop(t, segments, 4)
+
+	// In the bottom face plane, each bottom edge is reported once, by the
+	// side triangle above it
+	segments = SliceAtZ(cube, 0)
+	if len(segments) != 4 {
+		t.Fatalf("cube at 0: got %d segments, want 4", len(segments))
+	}
+	checkClosedLoop(t, segments, 4)
+
+	// The top face plane has nothing above it
+	if segments := SliceAtZ(cube, 1); len(segments) != 0 {
+		t.Errorf("cube at 1: got segments %v, want none", segments)
+	}
+
+	// Through two vertices and across the opposite edge, the section is a
+	// triangle with the edge between the vertices reported once
+	a, b, c, d := r3.Vec{Z: -1}, r3.Vec{X: 1}, r3.Vec{Y: 1}, r3.Vec{Z: 1}
+	diamond := []Triangle{
+		triangle(a, c, b),
+		triangle(a, b, d),
+		triangle(a, d, c),
+		triangle(b, c, d),
+	}
+	segments = SliceAtZ(diamond, 0)
+	if len(segments) != 3 {
+		t.Fatalf("through vertices: got %d segments, want 3", len(segments))
+	}
+	checkClosedLoop(t, segments, 2+math.Sqrt2)
+}
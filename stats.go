@@ -0,0 +1,113 @@
+package stl
+
+import (
+	"io"
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// Stats summarizes the triangles of an STL file
+type Stats struct {
+	// TriangleCount is the number of triangles parsed
+	TriangleCount int
+	// DegenerateCount is the number of triangles with zero area, matching
+	// DegenerateTriangles with an epsilon of math.SmallestNonzeroFloat64
+	DegenerateCount int
+	// MinEdgeLength and MaxEdgeLength are the shortest and longest triangle
+	// edges, as returned by MinEdgeLength and MaxEdgeLength
+	MinEdgeLength float64
+	MaxEdgeLength float64
+	// SurfaceArea is the total area, as returned by SurfaceArea
+	SurfaceArea float64
+	// Volume is the enclosed volume, as returned by MeshVolume
+	Volume float64
+	// Watertight reports whether every edge is shared by exactly two
+	// triangles, as IsWatertight does with a tol of 0
+	Watertight bool
+}
+
+// CalculateStats reads an STL file from the given io.Reader and returns its
+// bounding box along with summary statistics, computed in a single streaming
+// pass without buffering the triangles. Checking watertightness tracks the
+// distinct vertices and edges, so memory grows with the size of the mesh.
+func CalculateStats(r io.Reader, opts ...Option) (*BoundingBox, *Stats, error) {
+	bbox := newBoundingBox()
+	acc := &statsAccumulator{
+		stats:  &Stats{MinEdgeLength: math.Inf(1)},
+		welder: newVertexWelder(0),
+		edges:  make(map[edge]int),
+	}
+
+	cfg := newConfig(opts)
+	err := parse(r, cfg, &visitor{triangle: func(t Triangle) error {
+		updateBoundingBox(bbox, t.Vertices[:])
+		acc.add(t)
+		return nil
+	}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updateCenter(bbox)
+	bbox.Partial = cfg.partial
+	return bbox, acc.finish(), nil
+}
+
+// statsAccumulator gathers Stats one triangle at a time
+type statsAccumulator struct {
+	stats *Stats
+	// ref is the reference point of the volume tetrahedra, as in MeshVolume
+	ref    r3.Vec
+	volume kahanSum
+	welder *vertexWelder
+	edges  map[edge]int
+}
+
+// add accumulates a triangle into the statistics
+func (a *statsAccumulator) add(t Triangle) {
+	s := a.stats
+	v := t.Vertices
+	if s.TriangleCount == 0 {
+		a.ref = v[0]
+	}
+	s.TriangleCount++
+
+	if r3.Norm(r3.Cross(r3.Sub(v[1], v[0]), r3.Sub(v[2], v[0]))) < math.SmallestNonzeroFloat64 {
+		s.DegenerateCount++
+	}
+
+	for _, length := range edgeLengths(v) {
+		s.MinEdgeLength = min(s.MinEdgeLength, length)
+		s.MaxEdgeLength = max(s.MaxEdgeLength, length)
+	}
+
+	s.SurfaceArea += t.Area()
+
+	p, q, r := r3.Sub(v[0], a.ref), r3.Sub(v[1], a.ref), r3.Sub(v[2], a.ref)
+	a.volume.add(r3.Dot(p, r3.Cross(q, r)) / 6)
+
+	var face [3]int
+	for j := range v {
+		face[j] = a.welder.add(v[j])
+	}
+	for j := 0; j < 3; j++ {
+		if from, to := face[j], face[(j+1)%3]; from != to {
+			a.edges[newEdge(from, to)]++
+		}
+	}
+}
+
+// finish returns the accumulated statistics
+func (a *statsAccumulator) finish() *Stats {
+	s := a.stats
+	s.Volume = math.Abs(a.volume.value())
+	s.Watertight = s.TriangleCount > 0
+	for _, n := range a.edges {
+		if n != 2 {
+			s.Watertight = false
+			break
+		}
+	}
+	return s
+}
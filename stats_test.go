@@ -0,0 +1,58 @@
+package stl
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestCalculateStats(t *testing.T) {
+	bbox, stats, err := CalculateStats(bytes.NewReader(binarySTL(t, unitCube())))
+	if err != nil {
+		t.Fatalf("CalculateStats: %v", err)
+	}
+	want := Stats{
+		TriangleCount:   12,
+		DegenerateCount: 0,
+		MinEdgeLength:   1,
+		MaxEdgeLength:   math.Sqrt2,
+		SurfaceArea:     6,
+		Volume:          1,
+		Watertight:      true,
+	}
+	if !statsApproxEqual(*stats, want) {
+		t.Errorf("cube: stats = %+v, want %+v", *stats, want)
+	}
+	if bbox.MinVec() != (r3.Vec{}) || bbox.MaxVec() != (r3.Vec{X: 1, Y: 1, Z: 1}) {
+		t.Errorf("cube: bounding box = %v, want the unit cube", bbox)
+	}
+
+	// A collinear sliver is counted as degenerate and opens the mesh
+	sliver := triangle(r3.Vec{}, r3.Vec{X: 0.5}, r3.Vec{X: 1})
+	_, stats, err = CalculateStats(bytes.NewReader(binarySTL(t, append(unitCube(), sliver))))
+	if err != nil {
+		t.Fatalf("CalculateStats: %v", err)
+	}
+	want.TriangleCount = 13
+	want.DegenerateCount = 1
+	want.MinEdgeLength = 0.5
+	want.Watertight = false
+	if !statsApproxEqual(*stats, want) {
+		t.Errorf("with sliver: stats = %+v, want %+v", *stats, want)
+	}
+}
+
+// statsApproxEqual reports whether a and b are equal, up to rounding in the
+// float fields
+func statsApproxEqual(a, b Stats) bool {
+	near := func(x, y float64) bool { return math.Abs(x-y) <= 1e-9 }
+	return a.TriangleCount == b.TriangleCount &&
+		a.DegenerateCount == b.DegenerateCount &&
+		near(a.MinEdgeLength, b.MinEdgeLength) &&
+		near(a.MaxEdgeLength, b.MaxEdgeLength) &&
+		near(a.SurfaceArea, b.SurfaceArea) &&
+		near(a.Volume, b.Volume) &&
+		a.Watertight == b.Watertight
+}
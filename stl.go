@@ -2,13 +2,11 @@ package stl
 
 import (
 	"bufio"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
-	"math"
 	"os"
-	"strconv"
-	"strings"
 
 	"gonum.org/v1/gonum/spatial/r3"
 )
@@ -24,6 +22,9 @@ type BoundingBox struct {
 	MinX, MinY, MinZ float32
 	MaxX, MaxY, MaxZ float32
 	Center           r3.Vec
+	// Partial reports that parsing stopped early because of WithTriangleLimit,
+	// so the box only covers the triangles read
+	Partial bool
 }
 
 // Dimensions returns the width, height, and depth of the bounding box
@@ -39,172 +40,474 @@ func (bb *BoundingBox) Volume() float32 {
 
 // CalculateBoundingBoxFromFile reads an STL file from the given path
 // and returns its bounding box. Supports both binary and ASCII STL formats.
-func CalculateBoundingBoxFromFile(filePath string) (*BoundingBox, error) {
+func CalculateBoundingBoxFromFile(filePath string, opts ...Option) (*BoundingBox, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("error opening file: %w", err)
 	}
 	defer file.Close()
 
-	return CalculateBoundingBox(file)
+	return CalculateBoundingBox(file, opts...)
 }
 
 // CalculateBoundingBox reads an STL file from the given io.Reader
 // and returns its bounding box. Supports both binary and ASCII STL formats.
-// The function automatically detects the format.
-func CalculateBoundingBox(r io.Reader) (*BoundingBox, error) {
-	// Read first 80 bytes to check if it's ASCII or binary
-	header := make([]byte, 80)
-	n, err := io.ReadFull(r, header)
-	if err != nil && err != io.ErrUnexpectedEOF {
-		return nil, fmt.Errorf("error reading header: %w", err)
+// The function automatically detects the format unless WithFormat is given.
+// Triangles are streamed, so memory use does not grow with the file size.
+func CalculateBoundingBox(r io.Reader, opts ...Option) (*BoundingBox, error) {
+	return CalculateBoundingBoxContext(context.Background(), r, opts...)
+}
+
+// CalculateBoundingBoxContext is like CalculateBoundingBox but stops parsing
+// and returns ctx.Err() once ctx is done. The context is checked every
+// 4096 triangles for binary files and on every facet for ASCII files.
+func CalculateBoundingBoxContext(ctx context.Context, r io.Reader, opts ...Option) (*BoundingBox, error) {
+	cfg := newConfig(opts)
+	cfg.ctx = ctx
+
+	bbox := &BoundingBox{}
+	if err := calculateBoundingBox(r, cfg, bbox); err != nil {
+		return nil, err
 	}
+	return bbox, nil
+}
+
+// CalculateBoundingBoxInto reads an STL file from the given io.Reader and
+// stores its bounding box in bb, which is reset first. Reusing one box across
+// many files avoids allocating a new one per call. On error the contents of
+// bb are unspecified.
+func CalculateBoundingBoxInto(r io.Reader, bb *BoundingBox, opts ...Option) error {
+	return calculateBoundingBox(r, newConfig(opts), bb)
+}
 
-	// Check if it's ASCII by looking for "solid" keyword
-	headerStr := string(header[:n])
-	if strings.HasPrefix(strings.TrimSpace(headerStr), "solid") {
-		// Might be ASCII, need to verify by checking if "facet" follows
-		return parseASCII(io.MultiReader(strings.NewReader(headerStr), r))
+// calculateBoundingBox computes the bounding box of an STL file into bbox
+func calculateBoundingBox(r io.Reader, cfg *config, bbox *BoundingBox) error {
+	if cfg.parallel {
+		if result, ok, err := calculateParallel(r, cfg); ok {
+			if err != nil {
+				return err
+			}
+			*bbox = *result
+			return nil
+		} else if err != nil {
+			return err
+		}
 	}
 
-	// Binary STL format
-	return parseBinary(io.MultiReader(strings.NewReader(headerStr), r))
+	bbox.Reset()
+
+	err := parse(r, cfg, &visitor{triangle: func(t Triangle) error {
+		updateBoundingBox(bbox, t.Vertices[:])
+		return nil
+	}})
+	if err != nil {
+		return err
+	}
+
+	updateCenter(bbox)
+	bbox.Partial = cfg.partial
+	return nil
 }
 
-// binaryTriangle is used for reading binary STL format (float32)
-type binaryTriangle struct {
-	Normal   [3]float32
-	Vertices [3][3]float32
+// ForEachTriangle reads an STL file from the given io.Reader and calls fn for
+// each triangle in file order without buffering the mesh. Parsing stops at the
+// first error returned by fn, which is returned unchanged.
+func ForEachTriangle(r io.Reader, fn func(Triangle) error, opts ...Option) error {
+	return parse(r, newConfig(opts), &visitor{triangle: fn})
 }
 
-// parseBinary parses a binary STL file
-func parseBinary(r io.Reader) (*BoundingBox, error) {
-	// Skip 80-byte header
-	header := make([]byte, 80)
-	if _, err := io.ReadFull(r, header); err != nil {
-		return nil, fmt.Errorf("error reading header: %w", err)
+// BoundingBoxFromTriangles computes the bounding box of triangles already in
+// memory, using the same float32 bounds and center as the parsers. An empty
+// slice yields an empty box.
+func BoundingBoxFromTriangles(triangles []Triangle) *BoundingBox {
+	bbox := newBoundingBox()
+	for i := range triangles {
+		updateBoundingBox(bbox, triangles[i].Vertices[:])
 	}
+	updateCenter(bbox)
 
-	// Read number of triangles
-	var numTriangles uint32
-	if err := binary.Read(r, binary.LittleEndian, &numTriangles); err != nil {
-		return nil, fmt.Errorf("error reading number of triangles: %w", err)
+	return bbox
+}
+
+// ParseSTLFromFile reads an STL file from the given path
+// and returns all of its triangles. Supports both binary and ASCII STL formats.
+func ParseSTLFromFile(filePath string, opts ...Option) ([]Triangle, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
 	}
+	defer file.Close()
+
+	return ParseSTL(file, opts...)
+}
 
-	bbox := &BoundingBox{
-		MinX: math.MaxFloat32, MinY: math.MaxFloat32, MinZ: math.MaxFloat32,
-		MaxX: -math.MaxFloat32, MaxY: -math.MaxFloat32, MaxZ: -math.MaxFloat32,
+// ParseSTL reads an STL file from the given io.Reader and returns all of its
+// triangles, including facet normals. Supports both binary and ASCII STL formats.
+// The function automatically detects the format unless WithFormat is given.
+func ParseSTL(r io.Reader, opts ...Option) ([]Triangle, error) {
+	var triangles []Triangle
+
+	err := parse(r, newConfig(opts), &visitor{
+		solid: func(_ string, count int) {
+			// Binary files declare their count, so allocate once up front
+			if count > 0 && triangles == nil {
+				triangles = make([]Triangle, 0, preallocCount(count))
+			}
+		},
+		triangle: func(t Triangle) error {
+			triangles = append(triangles, t)
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	for i := 0; i < int(numTriangles); i++ {
-		var binTriangle binaryTriangle
-		if err := binary.Read(r, binary.LittleEndian, &binTriangle); err != nil {
-			return nil, fmt.Errorf("error reading triangle %d: %w", i, err)
-		}
+	return triangles, nil
+}
 
-		// Convert to r3.Vec
-		vertices := [3]r3.Vec{
-			{X: float64(binTriangle.Vertices[0][0]), Y: float64(binTriangle.Vertices[0][1]), Z: float64(binTriangle.Vertices[0][2])},
-			{X: float64(binTriangle.Vertices[1][0]), Y: float64(binTriangle.Vertices[1][1]), Z: float64(binTriangle.Vertices[1][2])},
-			{X: float64(binTriangle.Vertices[2][0]), Y: float64(binTriangle.Vertices[2][1]), Z: float64(binTriangle.Vertices[2][2])},
-		}
+// ParseSolids reads an STL file from the given io.Reader and returns the
+// triangles of each solid keyed by the name on its "solid NAME" line.
+// Unnamed solids use the empty string; solids sharing a name are merged.
+// Binary STL files always contain a single solid, keyed by "".
+func ParseSolids(r io.Reader, opts ...Option) (map[string][]Triangle, error) {
+	solids := make(map[string][]Triangle)
+	var current string
+
+	err := parse(r, newConfig(opts), &visitor{
+		solid: func(name string, count int) {
+			current = name
+			if _, ok := solids[name]; !ok {
+				solids[name] = make([]Triangle, 0, preallocCount(count))
+			}
+		},
+		triangle: func(t Triangle) error {
+			solids[current] = append(solids[current], t)
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return solids, nil
+}
 
-		updateBoundingBox(bbox, vertices[:])
+// SolidBox is the bounding box of one solid in an STL file
+type SolidBox struct {
+	Name          string
+	Box           *BoundingBox
+	TriangleCount int
+}
 
-		// Skip 2-byte attribute byte count
-		var attributeByteCount uint16
-		if err := binary.Read(r, binary.LittleEndian, &attributeByteCount); err != nil {
-			return nil, fmt.Errorf("error reading attribute byte count: %w", err)
+// CalculateBoundingBoxesPerSolid reads an STL file from the given io.Reader
+// and returns the bounding box of each solid in file order. Unlike
+// ParseSolids, solids sharing a name are kept separate, and solids without
+// triangles are omitted. Binary STL files return a single solid named "".
+func CalculateBoundingBoxesPerSolid(r io.Reader, opts ...Option) ([]SolidBox, error) {
+	var solids []SolidBox
+	var current *SolidBox
+
+	cfg := newConfig(opts)
+	err := parse(r, cfg, &visitor{
+		solid: func(name string, count int) {
+			solids = append(solids, SolidBox{Name: name, Box: newBoundingBox()})
+			current = &solids[len(solids)-1]
+		},
+		triangle: func(t Triangle) error {
+			updateBoundingBox(current.Box, t.Vertices[:])
+			current.TriangleCount++
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nonEmpty := solids[:0]
+	for _, solid := range solids {
+		if solid.TriangleCount > 0 {
+			updateCenter(solid.Box)
+			nonEmpty = append(nonEmpty, solid)
 		}
 	}
+	// Only the solid being parsed when WithTriangleLimit stopped is incomplete
+	if cfg.partial && len(nonEmpty) > 0 {
+		nonEmpty[len(nonEmpty)-1].Box.Partial = true
+	}
+	return nonEmpty, nil
+}
 
-	// Calculate center
-	bbox.Center = r3.Vec{
-		X: float64((bbox.MinX + bbox.MaxX) / 2),
-		Y: float64((bbox.MinY + bbox.MaxY) / 2),
-		Z: float64((bbox.MinZ + bbox.MaxZ) / 2),
+// visitor receives the contents of an STL file as it is parsed
+type visitor struct {
+	// solid, if set, is called at the start of each solid with its name and
+	// declared triangle count, or -1 if the count is unknown
+	solid func(name string, count int)
+	// attribute, if set, is called with the attribute field of each binary
+	// triangle just before triangle is called for it
+	attribute func(uint16)
+	// triangle is called for each triangle in file order
+	triangle func(Triangle) error
+}
+
+// beginSolid notifies v that a new solid has started
+func (v *visitor) beginSolid(name string, count int) {
+	if v.solid != nil {
+		v.solid(name, count)
 	}
+}
 
-	return bbox, nil
+// parse detects the format of r and feeds its contents to v
+func parse(r io.Reader, cfg *config, v *visitor) error {
+	size, err := cfg.expectedSize(r)
+	if err != nil {
+		return err
+	}
+
+	format, r, err := resolveFormat(r, cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.consumed != nil {
+		cfg.consumed.r = r
+		r = cfg.consumed
+	}
+
+	v = cfg.wrap(v)
+
+	if format == FormatASCII {
+		return cfg.limitReached(parseASCII(r, cfg, v))
+	}
+
+	// Binary STL format
+	return cfg.limitReached(parseBinary(r, cfg, size, v))
 }
 
-// parseASCII parses an ASCII STL file
-func parseASCII(r io.Reader) (*BoundingBox, error) {
-	scanner := bufio.NewScanner(r)
-	bbox := &BoundingBox{
-		MinX: math.MaxFloat32, MinY: math.MaxFloat32, MinZ: math.MaxFloat32,
-		MaxX: -math.MaxFloat32, MaxY: -math.MaxFloat32, MaxZ: -math.MaxFloat32,
+// TriangleCount returns the number of triangles in an STL file without
+// materializing them. For binary STL the count is read directly from the header;
+// for ASCII STL the file is streamed and "endfacet" keywords are counted.
+func TriangleCount(r io.Reader) (int, error) {
+	format, r, err := resolveFormat(r, newConfig(nil))
+	if err != nil {
+		return 0, err
 	}
 
-	var currentTriangle [3]r3.Vec
-	vertexIndex := 0
-	inFacet := false
+	if format == FormatASCII {
+		return countASCIIFacets(r)
+	}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		fields := strings.Fields(line)
+	numTriangles, err := readBinaryHeader(r)
+	if err != nil {
+		return 0, err
+	}
+	return int(numTriangles), nil
+}
 
-		if len(fields) == 0 {
-			continue
-		}
+// resolveFormat returns the format configured in cfg, detecting it from r if
+// unset. It returns a reader that yields the full, unconsumed contents of r.
+func resolveFormat(r io.Reader, cfg *config) (Format, io.Reader, error) {
+	if cfg.format != FormatUnknown {
+		return cfg.format, r, nil
+	}
 
-		switch fields[0] {
-		case "facet":
-			inFacet = true
-			vertexIndex = 0
-		case "vertex":
-			if !inFacet || len(fields) < 4 {
-				return nil, fmt.Errorf("invalid vertex line: %s", line)
-			}
-			if vertexIndex >= 3 {
-				return nil, fmt.Errorf("too many vertices in facet")
-			}
+	size, err := remainingSize(r)
+	if err != nil {
+		return FormatUnknown, nil, err
+	}
 
-			x, err := strconv.ParseFloat(fields[1], 64)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing x coordinate: %w", err)
-			}
-			y, err := strconv.ParseFloat(fields[2], 64)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing y coordinate: %w", err)
-			}
-			z, err := strconv.ParseFloat(fields[3], 64)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing z coordinate: %w", err)
-			}
+	// Peek at the leading bytes without consuming them. The parsers read from
+	// br, so the 80-byte header and triangle count are read exactly once, even
+	// from non-seekable streams and inputs shorter than detectWindow.
+	br := bufio.NewReaderSize(r, detectWindow)
+	data, err := br.Peek(detectWindow)
+	if err != nil && err != io.EOF {
+		return FormatUnknown, nil, fmt.Errorf("error reading header: %w", err)
+	}
+
+	format, err := detectedFormat(data, size)
+	if err != nil {
+		return FormatUnknown, nil, err
+	}
+	return format, br, nil
+}
+
+// detectedFormat classifies an STL file from its leading bytes for parsing.
+// Input that is not plausibly ASCII is parsed as binary so that truncated or
+// malformed files report where they went wrong, unless it is too short to
+// hold a binary header.
+func detectedFormat(data []byte, size int64) (Format, error) {
+	switch sniffFormat(data, size) {
+	case FormatASCII:
+		return FormatASCII, nil
+	case FormatBinary:
+		return FormatBinary, nil
+	}
+
+	if len(data) == 0 {
+		return FormatUnknown, fmt.Errorf("%w: empty input", ErrNotSTL)
+	}
+	if len(data) < 84 {
+		return FormatUnknown, fmt.Errorf("%w: input too short (%d bytes)", ErrNotSTL, len(data))
+	}
+	return FormatBinary, nil
+}
+
+// checkInterval is how many triangles are parsed between progress reports,
+// and between context checks for binary files
+const checkInterval = 4096
+
+// maxPrealloc caps how many triangles are allocated up front from a declared
+// binary count, so a corrupt header cannot force a huge allocation
+const maxPrealloc = 1 << 20
+
+// preallocCount returns the capacity to reserve for a solid declaring count
+// triangles (-1 if unknown)
+func preallocCount(count int) int {
+	return min(max(count, 0), maxPrealloc)
+}
+
+// BinaryTriangle is the float32 normal and vertices of a triangle record in a
+// binary STL file, as read by BinaryTriangleReader
+type BinaryTriangle struct {
+	Normal   [3]float32
+	Vertices [3][3]float32
+}
 
-			currentTriangle[vertexIndex] = r3.Vec{
-				X: x,
-				Y: y,
-				Z: z,
+// parseBinary parses a binary STL file of the given size, or -1 if unknown
+func parseBinary(r io.Reader, cfg *config, size int64, v *visitor) error {
+	numTriangles, err := readBinaryHeader(r)
+	if err != nil {
+		return err
+	}
+
+	order, numTriangles := cfg.binaryOrder(numTriangles, size)
+	if err := checkBinaryHeader(cfg, numTriangles, size); err != nil {
+		return err
+	}
+
+	v.beginSolid("", int(numTriangles))
+
+	record := make([]byte, 50)
+	accepted := 0
+	for i := 0; i < int(numTriangles); i++ {
+		if i%checkInterval == 0 {
+			if err := cfg.ctx.Err(); err != nil {
+				return err
 			}
-			vertexIndex++
-		case "endfacet":
-			if vertexIndex != 3 {
-				return nil, fmt.Errorf("incomplete triangle, got %d vertices", vertexIndex)
+			cfg.reportProgress(i, int(numTriangles))
+		}
+
+		triangle, attributeByteCount, err := readBinaryTriangle(r, record, i, order)
+		if err != nil {
+			return err
+		}
+		if v.attribute != nil {
+			v.attribute(attributeByteCount)
+		}
+		ok, err := visitTriangle(v, triangle)
+		if err == errTriangleLimit && i == int(numTriangles)-1 {
+			// The limit fell on the last declared triangle, so the file was
+			// read in full
+			ok, err = true, nil
+		}
+		if err != nil {
+			return err
+		}
+		if ok {
+			accepted++
+		}
+	}
+	if accepted == 0 {
+		return allSkipped(int(numTriangles))
+	}
+
+	cfg.reportProgress(int(numTriangles), int(numTriangles))
+	return nil
+}
+
+// checkBinaryHeader validates the declared triangle count of a binary STL file
+// against the configured limit and, with WithValidateSize and a non-negative
+// size, the file size. Files declaring no triangles return ErrEmptyMesh.
+func checkBinaryHeader(cfg *config, numTriangles uint32, size int64) error {
+	if cfg.exceedsMaxTriangles(int(numTriangles)) {
+		return fmt.Errorf("file declares %d triangles, exceeding the limit of %d", numTriangles, cfg.maxTriangles)
+	}
+
+	if cfg.validateSize && size >= 0 {
+		expected := binaryFileSize(numTriangles)
+		if size < expected {
+			return fmt.Errorf("%w: file size mismatch: expected %d bytes for %d triangles, got %d", ErrTruncated, expected, numTriangles, size)
+		}
+		if size > expected {
+			return fmt.Errorf("file size mismatch: expected %d bytes for %d triangles, got %d", expected, numTriangles, size)
+		}
+	}
+
+	if numTriangles == 0 {
+		return fmt.Errorf("%w: binary STL file declares no triangles", ErrEmptyMesh)
+	}
+
+	return nil
+}
+
+// readBinaryTriangle reads the 50-byte record of the i-th triangle of a binary
+// STL file in the given byte order into record, returning the triangle and its
+// attribute byte count. Reusing record across calls and decoding it directly
+// avoids the reflection and per-triangle allocations of binary.Read.
+func readBinaryTriangle(r io.Reader, record []byte, i int, order binary.ByteOrder) (Triangle, uint16, error) {
+	n, err := io.ReadFull(r, record[:50])
+	if err != nil {
+		// A record cut off after its vertices is missing its attribute count
+		if n >= 48 {
+			if n == 48 {
+				err = io.EOF
 			}
-			updateBoundingBox(bbox, currentTriangle[:])
-			inFacet = false
+			return Triangle{}, 0, readError("error reading attribute byte count", err)
 		}
+		return Triangle{}, 0, readError(fmt.Sprintf("error reading triangle %d", i), err)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+	return decodeBinaryTriangle(record, order), order.Uint16(record[48:50]), nil
+}
+
+// readBinaryHeader skips the 80-byte header of a binary STL file
+// and returns the number of triangles it declares
+func readBinaryHeader(r io.Reader) (uint32, error) {
+	// Skip 80-byte header
+	header := make([]byte, 80)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, readError("error reading header", err)
 	}
 
-	// Check if we found any triangles
-	if bbox.MinX == math.MaxFloat32 {
-		return nil, fmt.Errorf("no triangles found in STL file")
+	// Read number of triangles
+	var numTriangles uint32
+	if err := binary.Read(r, binary.LittleEndian, &numTriangles); err != nil {
+		return 0, readError("error reading number of triangles", err)
 	}
 
-	// Calculate center
+	return numTriangles, nil
+}
+
+// binaryFileSize returns the size in bytes of a binary STL file
+// holding the given number of triangles
+func binaryFileSize(numTriangles uint32) int64 {
+	return 84 + int64(numTriangles)*50
+}
+
+// newBoundingBox returns an empty bounding box ready to be updated
+func newBoundingBox() *BoundingBox {
+	bbox := &BoundingBox{}
+	bbox.Reset()
+	return bbox
+}
+
+// updateCenter sets the center of the bounding box from its min and max
+func updateCenter(bbox *BoundingBox) {
 	bbox.Center = r3.Vec{
 		X: float64((bbox.MinX + bbox.MaxX) / 2),
 		Y: float64((bbox.MinY + bbox.MaxY) / 2),
 		Z: float64((bbox.MinZ + bbox.MaxZ) / 2),
 	}
-
-	return bbox, nil
 }
 
 // updateBoundingBox updates the bounding box with the given vertices
@@ -0,0 +1,359 @@
+package stl
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// binarySTL returns triangles encoded as a binary STL file
+func binarySTL(t testing.TB, triangles []Triangle) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, triangles); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// asciiSTL returns triangles encoded as an ASCII STL file named name
+func asciiSTL(t testing.TB, name string, triangles []Triangle) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := WriteASCII(&buf, name, triangles); err != nil {
+		t.Fatalf("WriteASCII: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// triangle returns a triangle with the given vertices and a zero normal
+func triangle(a, b, c r3.Vec) Triangle {
+	return Triangle{Vertices: [3]r3.Vec{a, b, c}}
+}
+
+// unitCube returns the 12 outward-wound triangles of the cube [0, 1]^3 with
+// their normals set
+func unitCube() []Triangle {
+	// Corners of each face, counter-clockwise when viewed from outside
+	faces := [6][4]r3.Vec{
+		{{}, {Y: 1}, {X: 1, Y: 1}, {X: 1}},
+		{{Z: 1}, {X: 1, Z: 1}, {X: 1, Y: 1, Z: 1}, {Y: 1, Z: 1}},
+		{{}, {X: 1}, {X: 1, Z: 1}, {Z: 1}},
+		{{Y: 1}, {Y: 1, Z: 1}, {X: 1, Y: 1, Z: 1}, {X: 1, Y: 1}},
+		{{}, {Z: 1}, {Y: 1, Z: 1}, {Y: 1}},
+		{{X: 1}, {X: 1, Y: 1}, {X: 1, Y: 1, Z: 1}, {X: 1, Z: 1}},
+	}
+
+	var triangles []Triangle
+	for _, f := range faces {
+		triangles = append(triangles, triangle(f[0], f[1], f[2]), triangle(f[0], f[2], f[3]))
+	}
+	RecomputeNormals(triangles)
+	return triangles
+}
+
+// cuboid returns the unit cube scaled to the given size, with its minimum
+// corner at the origin
+func cuboid(size r3.Vec) []Triangle {
+	triangles := unitCube()
+	for i := range triangles {
+		for j, v := range triangles[i].Vertices {
+			triangles[i].Vertices[j] = r3.Vec{X: v.X * size.X, Y: v.Y * size.Y, Z: v.Z * size.Z}
+		}
+	}
+	return triangles
+}
+
+// strip returns n triangles forming a strip along the x axis
+func strip(n int) []Triangle {
+	triangles := make([]Triangle, n)
+	for i := range triangles {
+		x := float64(i)
+		triangles[i] = triangle(r3.Vec{X: x}, r3.Vec{X: x + 1}, r3.Vec{X: x, Y: 1, Z: x / 2})
+	}
+	return triangles
+}
+
+func BenchmarkParseBinary(b *testing.B) {
+	data := binarySTL(b, strip(100000))
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := CalculateBoundingBox(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestTrailingJunk(t *testing.T) {
+	triangles := strip(3)
+	want := BoundingBoxFromTriangles(triangles)
+
+	t.Run("ascii", func(t *testing.T) {
+		data := asciiSTL(t, "part", triangles)
+		data = append(data, "endsolid part\nExported by Modeler 2.1\nfacet normal 0 0 1\n  vertex 100 100 100\n"...)
+
+		got, err := ParseSTL(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("ParseSTL: %v", err)
+		}
+		if len(got) != len(triangles) {
+			t.Errorf("got %d triangles, want %d", len(got), len(triangles))
+		}
+		if n, err := TriangleCount(bytes.NewReader(data)); err != nil || n != len(triangles) {
+			t.Errorf("TriangleCount = %d, %v, want %d", n, err, len(triangles))
+		}
+
+		bbox, err := CalculateBoundingBox(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("CalculateBoundingBox: %v", err)
+		}
+		if !bbox.Equal(want) {
+			t.Errorf("got box %+v, want %+v", *bbox, *want)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		data := binarySTL(t, triangles)
+		data = append(data, bytes.Repeat([]byte{0x7f}, 73)...)
+
+		bbox, err := CalculateBoundingBox(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("CalculateBoundingBox: %v", err)
+		}
+		if !bbox.Equal(want) {
+			t.Errorf("got box %+v, want %+v", *bbox, *want)
+		}
+
+		bbox, err = CalculateBoundingBoxFromBytes(data)
+		if err != nil {
+			t.Fatalf("CalculateBoundingBoxFromBytes: %v", err)
+		}
+		if !bbox.Equal(want) {
+			t.Errorf("from bytes: got box %+v, want %+v", *bbox, *want)
+		}
+
+		if _, err := CalculateBoundingBox(bytes.NewReader(data), WithValidateSize(true)); err == nil {
+			t.Errorf("WithValidateSize accepted %d trailing bytes", 73)
+		}
+	})
+}
+
+func TestHeaderReadOnce(t *testing.T) {
+	triangles := strip(2)
+	want := BoundingBoxFromTriangles(triangles)
+
+	plain := binarySTL(t, triangles)
+	// A header starting with "solid" must not be mistaken for ASCII either
+	solid := bytes.Clone(plain)
+	copy(solid, "solid exported by CAD")
+
+	readers := []struct {
+		name string
+		wrap func(io.Reader) io.Reader
+	}{
+		{"one byte", iotest.OneByteReader},
+		{"half", iotest.HalfReader},
+		{"data with error", iotest.DataErrReader},
+	}
+
+	for _, input := range []struct {
+		name string
+		data []byte
+	}{{"zero header", plain}, {"solid header", solid}} {
+		for _, reader := range readers {
+			t.Run(input.name+"/"+reader.name, func(t *testing.T) {
+				r := &countingReader{r: reader.wrap(bytes.NewReader(input.data))}
+				bbox, err := CalculateBoundingBox(r)
+				if err != nil {
+					t.Fatalf("CalculateBoundingBox: %v", err)
+				}
+				if !bbox.Equal(want) {
+					t.Errorf("got box %+v, want %+v", *bbox, *want)
+				}
+				if r.n != int64(len(input.data)) {
+					t.Errorf("read %d bytes, want %d", r.n, len(input.data))
+				}
+			})
+		}
+	}
+}
+
+func TestShortInput(t *testing.T) {
+	for _, n := range []int{0, 1, 50, 80, 83} {
+		data := bytes.Repeat([]byte{0x01}, n)
+
+		for _, short := range []bool{false, true} {
+			var r io.Reader = bytes.NewReader(data)
+			if short {
+				r = iotest.OneByteReader(r)
+			}
+			if _, err := CalculateBoundingBox(r); !errors.Is(err, ErrNotSTL) {
+				t.Errorf("%d bytes, short reads %v: got error %v, want ErrNotSTL", n, short, err)
+			}
+		}
+
+		if n == 0 {
+			continue
+		}
+		r := iotest.OneByteReader(bytes.NewReader(data))
+		if _, err := CalculateBoundingBox(r, WithFormat(FormatBinary)); !errors.Is(err, ErrTruncated) {
+			t.Errorf("%d bytes as binary: got error %v, want ErrTruncated", n, err)
+		}
+	}
+}
+
+func TestASCIIBOMAndLineEndings(t *testing.T) {
+	want := BoundingBoxFromTriangles(unitCube())
+	plain := asciiSTL(t, "cube", unitCube())
+
+	for _, tt := range []struct {
+		name   string
+		ending string
+	}{
+		{"crlf", "\r\n"},
+		{"lone cr", "\r"},
+		{"lf", "\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data := append(bytes.Clone(utf8BOM), bytes.ReplaceAll(plain, []byte("\n"), []byte(tt.ending))...)
+
+			if format, err := DetectFormat(bytes.NewReader(data)); err != nil || format != FormatASCII {
+				t.Errorf("DetectFormat = %v, %v, want FormatASCII", format, err)
+			}
+
+			solids, err := ParseSolids(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("ParseSolids: %v", err)
+			}
+			if len(solids["cube"]) != 12 {
+				t.Errorf("got solids %v, want 12 triangles named cube", solids)
+			}
+
+			bbox, err := CalculateBoundingBox(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("CalculateBoundingBox: %v", err)
+			}
+			if !bbox.Equal(want) {
+				t.Errorf("got box %+v, want %+v", *bbox, *want)
+			}
+
+			// Each line ending counts as one line when reporting problems
+			broken := bytes.Replace(data, []byte("vertex 0 0 0"), []byte("vertex 0 0 x"), 1)
+			_, warnings, err := CalculateBoundingBoxWithWarnings(bytes.NewReader(broken), WithSkipInvalid(true))
+			if err != nil {
+				t.Fatalf("CalculateBoundingBoxWithWarnings: %v", err)
+			}
+			if len(warnings) != 1 || warnings[0].Line != 4 {
+				t.Errorf("got warnings %v, want one on line 4", warnings)
+			}
+		})
+	}
+}
+
+func TestUnicodeSolidName(t *testing.T) {
+	const name = "Gehäuse oben – 東京 v2"
+	cube := unitCube()
+
+	// Extra whitespace around the name is not part of it
+	written := asciiSTL(t, name, cube)
+	fixture := bytes.Replace(written, []byte("solid "+name+"\n"), []byte("solid \t "+name+"  \r\n"), 1)
+	single := bytes.Join(bytes.Fields(written), []byte(" "))
+
+	for _, tt := range []struct {
+		label string
+		data  []byte
+	}{
+		{"fixture", fixture},
+		{"single line", single},
+		{"written", written},
+	} {
+		t.Run(tt.label, func(t *testing.T) {
+			solids, err := ParseSolids(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("ParseSolids: %v", err)
+			}
+			if len(solids) != 1 || len(solids[name]) != len(cube) {
+				t.Fatalf("got solids %v, want %d triangles named %q", solids, len(cube), name)
+			}
+
+			// The name survives writing and parsing again
+			var buf bytes.Buffer
+			if err := WriteASCII(&buf, name, solids[name]); err != nil {
+				t.Fatalf("WriteASCII: %v", err)
+			}
+			if !bytes.HasPrefix(buf.Bytes(), []byte("solid "+name+"\n")) || !bytes.HasSuffix(buf.Bytes(), []byte("endsolid "+name+"\n")) {
+				t.Errorf("WriteASCII did not emit the name verbatim")
+			}
+			boxes, err := CalculateBoundingBoxesPerSolid(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("CalculateBoundingBoxesPerSolid: %v", err)
+			}
+			if len(boxes) != 1 || boxes[0].Name != name {
+				t.Errorf("got solids %+v, want one named %q", boxes, name)
+			}
+		})
+	}
+}
+
+func TestKeywordSolidName(t *testing.T) {
+	cube := unitCube()
+	next := bytes.Join(bytes.Fields(asciiSTL(t, "next", cube)), []byte(" "))
+
+	// Keywords inside a name only end it where facet syntax begins
+	for _, name := range []string{"facet plate", "endsolid block", "solid", "plate facet"} {
+		written := asciiSTL(t, name, cube)
+		single := bytes.Join(bytes.Fields(written), []byte(" "))
+
+		for _, tt := range []struct {
+			label  string
+			data   []byte
+			solids int
+		}{
+			{"written", written, 1},
+			{"single line", single, 1},
+			{"concatenated", append(append(single, ' '), next...), 2},
+		} {
+			solids, err := ParseSolids(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("%q %s: ParseSolids: %v", name, tt.label, err)
+			}
+			if len(solids) != tt.solids || len(solids[name]) != len(cube) {
+				t.Errorf("%q %s: got solids %v, want %d with %d triangles named %q", name, tt.label, solids, tt.solids, len(cube), name)
+			}
+		}
+	}
+}
+
+func TestZeroTriangleBinary(t *testing.T) {
+	plain := binarySTL(t, nil)
+	solid := bytes.Clone(plain)
+	copy(solid, "solid empty")
+
+	for _, data := range [][]byte{plain, solid} {
+		if len(data) != 84 {
+			t.Fatalf("fixture has %d bytes, want 84", len(data))
+		}
+
+		if _, err := CalculateBoundingBox(bytes.NewReader(data)); !errors.Is(err, ErrEmptyMesh) {
+			t.Errorf("CalculateBoundingBox: got error %v, want ErrEmptyMesh", err)
+		}
+		if _, err := CalculateBoundingBox(bytes.NewReader(data), WithParallel(true)); !errors.Is(err, ErrEmptyMesh) {
+			t.Errorf("parallel: got error %v, want ErrEmptyMesh", err)
+		}
+		if _, err := CalculateBoundingBoxFromBytes(data); !errors.Is(err, ErrEmptyMesh) {
+			t.Errorf("CalculateBoundingBoxFromBytes: got error %v, want ErrEmptyMesh", err)
+		}
+		if _, err := ParseSTL(iotest.OneByteReader(bytes.NewReader(data))); !errors.Is(err, ErrEmptyMesh) {
+			t.Errorf("ParseSTL: got error %v, want ErrEmptyMesh", err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package stl
+
+import (
+	"slices"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// Plane is an infinite plane through Point with unit normal Normal
+type Plane struct {
+	Point  r3.Vec
+	Normal r3.Vec
+}
+
+// Reflect returns the mirror image of p across the plane
+func (pl Plane) Reflect(p r3.Vec) r3.Vec {
+	d := r3.Dot(r3.Sub(p, pl.Point), pl.Normal)
+	return r3.Sub(p, r3.Scale(2*d, pl.Normal))
+}
+
+// DetectSymmetry returns the principal planes of the given triangles about
+// which the mesh is mirror symmetric. The candidates pass through the center
+// of the oriented bounding box of the distinct vertices, normal to each of its
+// axes; a mesh is symmetric about one if every vertex reflected across it lies
+// within tol of some vertex. Planes are returned in the order of the box axes,
+// from greatest to least vertex variance. An empty slice yields no planes.
+func DetectSymmetry(triangles []Triangle, tol float64) []Plane {
+	if len(triangles) == 0 {
+		return nil
+	}
+
+	welder := newVertexWelder(tol)
+	for i := range triangles {
+		for _, v := range triangles[i].Vertices {
+			welder.add(v)
+		}
+	}
+
+	// Use each distinct vertex once, so the axes are not skewed by how often
+	// the triangulation happens to reuse vertices
+	obb := orientedBoundingBox(slices.Values(welder.vertices))
+	var planes []Plane
+	for _, axis := range obb.Axes {
+		plane := Plane{Point: obb.Center, Normal: axis}
+		if isMirrorSymmetric(welder, plane) {
+			planes = append(planes, plane)
+		}
+	}
+	return planes
+}
+
+// isMirrorSymmetric reports whether every vertex of welder reflected across
+// plane lies within the welder's tolerance of one of its vertices
+func isMirrorSymmetric(welder *vertexWelder, plane Plane) bool {
+	for _, v := range welder.vertices {
+		if _, ok := welder.find(plane.Reflect(v)); !ok {
+			return false
+		}
+	}
+	return true
+}
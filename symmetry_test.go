@@ -0,0 +1,43 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestDetectSymmetry(t *testing.T) {
+	// An axis-aligned box is symmetric about the three mid planes
+	planes := DetectSymmetry(cuboid(r3.Vec{X: 1, Y: 2, Z: 3}), 1e-9)
+	if len(planes) != 3 {
+		t.Fatalf("box: got %d planes, want 3", len(planes))
+	}
+	center := r3.Vec{X: 0.5, Y: 1, Z: 1.5}
+	wantNormals := [3]r3.Vec{{Z: 1}, {Y: 1}, {X: 1}}
+	for k, plane := range planes {
+		if r3.Norm(r3.Sub(plane.Point, center)) > 1e-9 {
+			t.Errorf("box: plane %d point = %v, want %v", k, plane.Point, center)
+		}
+		if math.Abs(math.Abs(r3.Dot(plane.Normal, wantNormals[k]))-1) > 1e-9 {
+			t.Errorf("box: plane %d normal = %v, want ±%v", k, plane.Normal, wantNormals[k])
+		}
+	}
+
+	// A tetrahedron with three different edge lengths at the corner has no
+	// mirror symmetry
+	o, x, y, z := r3.Vec{}, r3.Vec{X: 1}, r3.Vec{Y: 2}, r3.Vec{Z: 3}
+	scalene := []Triangle{
+		triangle(o, y, x),
+		triangle(o, x, z),
+		triangle(o, z, y),
+		triangle(x, y, z),
+	}
+	if planes := DetectSymmetry(scalene, 1e-6); len(planes) != 0 {
+		t.Errorf("scalene tetrahedron: got planes %v, want none", planes)
+	}
+
+	if planes := DetectSymmetry(nil, 1e-6); planes != nil {
+		t.Errorf("empty: got planes %v, want nil", planes)
+	}
+}
@@ -0,0 +1,47 @@
+package stl
+
+import (
+	"fmt"
+	"io"
+)
+
+// defaultPrecision is the number of decimal places used by String
+const defaultPrecision = 5
+
+// String returns a multi-line report of the bounding box's min, max,
+// dimensions, center, and volume, as printed by the command-line tool
+func (bb *BoundingBox) String() string {
+	return fmt.Sprintf("%v", bb)
+}
+
+// Format implements fmt.Formatter so that the %v and %s verbs print the report
+// returned by String. A precision sets the number of decimal places, e.g.
+// fmt.Printf("%.2v", bb).
+func (bb *BoundingBox) Format(f fmt.State, verb rune) {
+	if verb != 'v' && verb != 's' {
+		fmt.Fprintf(f, "%%!%c(*stl.BoundingBox)", verb)
+		return
+	}
+	if bb == nil {
+		io.WriteString(f, "<nil>")
+		return
+	}
+
+	p, ok := f.Precision()
+	if !ok {
+		p = defaultPrecision
+	}
+	width, height, depth := bb.Dimensions()
+	fmt.Fprintf(f, "Bounding Box:\n")
+	fmt.Fprintf(f, "  Min: (%.*f, %.*f, %.*f)\n", p, bb.MinX, p, bb.MinY, p, bb.MinZ)
+	fmt.Fprintf(f, "  Max: (%.*f, %.*f, %.*f)\n", p, bb.MaxX, p, bb.MaxY, p, bb.MaxZ)
+	fmt.Fprintf(f, "  Dimensions: (%.*f, %.*f, %.*f)\n", p, width, p, height, p, depth)
+	fmt.Fprintf(f, "  Center: (%.*f, %.*f, %.*f)\n", p, bb.Center.X, p, bb.Center.Y, p, bb.Center.Z)
+	fmt.Fprintf(f, "  Volume: %.*f", p, bb.Volume())
+}
+
+// WriteTo writes the report returned by String to w, followed by a newline
+func (bb *BoundingBox) WriteTo(w io.Writer) (int64, error) {
+	n, err := fmt.Fprintln(w, bb)
+	return int64(n), err
+}
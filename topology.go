@@ -0,0 +1,283 @@
+package stl
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// edge is an undirected mesh edge, stored as sorted vertex indices
+type edge [2]int
+
+// newEdge returns the undirected edge between vertices a and b
+func newEdge(a, b int) edge {
+	if a > b {
+		a, b = b, a
+	}
+	return edge{a, b}
+}
+
+// countEdges returns how many faces use each undirected edge.
+// Edges collapsed to a single vertex by welding are ignored.
+func countEdges(faces [][3]int) map[edge]int {
+	counts := make(map[edge]int, len(faces)*3/2)
+	for _, f := range faces {
+		for j := 0; j < 3; j++ {
+			a, b := f[j], f[(j+1)%3]
+			if a != b {
+				counts[newEdge(a, b)]++
+			}
+		}
+	}
+	return counts
+}
+
+// OpenEdges returns the number of edges used by only one of the given
+// triangles after welding vertices within tol of each other. A closed mesh
+// has no open edges.
+func OpenEdges(triangles []Triangle, tol float64) int {
+	open := 0
+	for _, n := range countEdges(NewIndexedMesh(triangles, tol).Faces) {
+		if n == 1 {
+			open++
+		}
+	}
+	return open
+}
+
+// IsWatertight reports whether the given triangles form a closed mesh, i.e.
+// every edge is shared by exactly two triangles after welding vertices within tol
+// of each other. Since float32 STL coordinates rarely match exactly, tol should
+// be small but non-zero. If the mesh is not watertight, the returned error
+// reports how many edges are open or shared by more than two triangles.
+func IsWatertight(triangles []Triangle, tol float64) (bool, error) {
+	if len(triangles) == 0 {
+		return false, fmt.Errorf("%w: no triangles in mesh", ErrEmptyMesh)
+	}
+
+	open, nonManifold := 0, 0
+	for _, n := range countEdges(NewIndexedMesh(triangles, tol).Faces) {
+		switch {
+		case n == 1:
+			open++
+		case n > 2:
+			nonManifold++
+		}
+	}
+
+	if open > 0 || nonManifold > 0 {
+		return false, fmt.Errorf("mesh is not watertight: %d open edges, %d non-manifold edges", open, nonManifold)
+	}
+	return true, nil
+}
+
+// NonManifoldEdges returns the edges of the mesh shared by more than two faces,
+// as pairs of vertex indices with the smaller index first, sorted ascending.
+func NonManifoldEdges(mesh IndexedMesh) [][2]int {
+	var edges [][2]int
+	for e, n := range countEdges(mesh.Faces) {
+		if n > 2 {
+			edges = append(edges, e)
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i][0] != edges[j][0] {
+			return edges[i][0] < edges[j][0]
+		}
+		return edges[i][1] < edges[j][1]
+	})
+	return edges
+}
+
+// ConsistentWinding reports whether adjacent faces of the mesh traverse each
+// shared edge in opposite directions, as required for outward normals and a
+// meaningful volume. If not, it also returns the first offending face: the
+// lowest-index face that traverses an edge in the same direction as an
+// earlier face. Consistent meshes return -1.
+func ConsistentWinding(mesh IndexedMesh) (bool, int) {
+	seen := make(map[[2]int]struct{}, len(mesh.Faces)*3)
+	for i, f := range mesh.Faces {
+		for j := 0; j < 3; j++ {
+			directed := [2]int{f[j], f[(j+1)%3]}
+			if directed[0] == directed[1] {
+				continue
+			}
+			if _, ok := seen[directed]; ok {
+				return false, i
+			}
+			seen[directed] = struct{}{}
+		}
+	}
+	return true, -1
+}
+
+// FixWinding returns a copy of the mesh with faces flipped so that adjacent
+// faces traverse each shared edge in opposite directions. Orientation is
+// propagated across shared edges from the lowest-index face of each connected
+// component, and a component whose signed volume is then negative is flipped
+// entirely so its normals point outward. The vertices are shared with mesh.
+func FixWinding(mesh IndexedMesh) IndexedMesh {
+	faces := make([][3]int, len(mesh.Faces))
+	copy(faces, mesh.Faces)
+
+	edgeFaces := make(map[edge][]int, len(faces)*3/2)
+	for i, f := range faces {
+		for j := 0; j < 3; j++ {
+			if a, b := f[j], f[(j+1)%3]; a != b {
+				e := newEdge(a, b)
+				edgeFaces[e] = append(edgeFaces[e], i)
+			}
+		}
+	}
+
+	visited := make([]bool, len(faces))
+	for seed := range faces {
+		if visited[seed] {
+			continue
+		}
+
+		visited[seed] = true
+		component := []int{seed}
+		for k := 0; k < len(component); k++ {
+			f := faces[component[k]]
+			for j := 0; j < 3; j++ {
+				a, b := f[j], f[(j+1)%3]
+				if a == b {
+					continue
+				}
+				for _, neighbour := range edgeFaces[newEdge(a, b)] {
+					if visited[neighbour] {
+						continue
+					}
+					// A consistent neighbour traverses the edge from b to a
+					if hasDirectedEdge(faces[neighbour], a, b) {
+						flipFace(&faces[neighbour])
+					}
+					visited[neighbour] = true
+					component = append(component, neighbour)
+				}
+			}
+		}
+
+		if signedVolume(mesh.Vertices, faces, component) < 0 {
+			for _, i := range component {
+				flipFace(&faces[i])
+			}
+		}
+	}
+
+	return IndexedMesh{Vertices: mesh.Vertices, Faces: faces}
+}
+
+// hasDirectedEdge reports whether face f traverses the edge from a to b
+func hasDirectedEdge(f [3]int, a, b int) bool {
+	for j := 0; j < 3; j++ {
+		if f[j] == a && f[(j+1)%3] == b {
+			return true
+		}
+	}
+	return false
+}
+
+// flipFace reverses the winding of face f
+func flipFace(f *[3]int) {
+	f[1], f[2] = f[2], f[1]
+}
+
+// signedVolume returns the signed volume enclosed by the given faces, which is
+// positive when their normals point outward
+func signedVolume(vertices []r3.Vec, faces [][3]int, component []int) float64 {
+	ref := vertices[faces[component[0]][0]]
+	var sum kahanSum
+	for _, i := range component {
+		f := faces[i]
+		a, b, c := r3.Sub(vertices[f[0]], ref), r3.Sub(vertices[f[1]], ref), r3.Sub(vertices[f[2]], ref)
+		sum.add(r3.Dot(a, r3.Cross(b, c)) / 6)
+	}
+	return sum.value()
+}
+
+// ConnectedComponents groups the faces of the mesh into sets connected through
+// shared edges. Each component lists face indices in ascending order, and
+// components are sorted by descending face count so the main object is first.
+func ConnectedComponents(mesh IndexedMesh) [][]int {
+	uf := newUnionFind(len(mesh.Faces))
+
+	// Join each face with the first face seen on each of its edges
+	firstFace := make(map[edge]int, len(mesh.Faces)*3/2)
+	for i, f := range mesh.Faces {
+		for j := 0; j < 3; j++ {
+			a, b := f[j], f[(j+1)%3]
+			if a == b {
+				continue
+			}
+			e := newEdge(a, b)
+			if other, ok := firstFace[e]; ok {
+				uf.union(i, other)
+			} else {
+				firstFace[e] = i
+			}
+		}
+	}
+
+	groups := make(map[int]int) // root -> index into components
+	var components [][]int
+	for i := range mesh.Faces {
+		root := uf.find(i)
+		c, ok := groups[root]
+		if !ok {
+			c = len(components)
+			groups[root] = c
+			components = append(components, nil)
+		}
+		components[c] = append(components[c], i)
+	}
+
+	// Components are discovered in order of their lowest face index,
+	// so a stable sort keeps ties in file order
+	sort.SliceStable(components, func(i, j int) bool {
+		return len(components[i]) > len(components[j])
+	})
+	return components
+}
+
+// unionFind is a disjoint-set forest over the integers [0, n)
+type unionFind struct {
+	parent []int
+	rank   []int
+}
+
+// newUnionFind returns a disjoint-set forest of n singleton sets
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n), rank: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+	}
+	return uf
+}
+
+// find returns the representative of the set containing x
+func (uf *unionFind) find(x int) int {
+	for uf.parent[x] != x {
+		uf.parent[x] = uf.parent[uf.parent[x]]
+		x = uf.parent[x]
+	}
+	return x
+}
+
+// union merges the sets containing a and b
+func (uf *unionFind) union(a, b int) {
+	ra, rb := uf.find(a), uf.find(b)
+	if ra == rb {
+		return
+	}
+	if uf.rank[ra] < uf.rank[rb] {
+		ra, rb = rb, ra
+	}
+	uf.parent[rb] = ra
+	if uf.rank[ra] == uf.rank[rb] {
+		uf.rank[ra]++
+	}
+}
@@ -0,0 +1,140 @@
+package stl
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// flipped returns a copy of triangles with the faces at the given indices
+// wound the other way
+func flipped(triangles []Triangle, indices ...int) []Triangle {
+	result := make([]Triangle, len(triangles))
+	copy(result, triangles)
+	for _, i := range indices {
+		v := &result[i].Vertices
+		v[1], v[2] = v[2], v[1]
+	}
+	return result
+}
+
+// upTo returns the indices 0 through n-1
+func upTo(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+func TestFixWinding(t *testing.T) {
+	for label, triangles := range map[string][]Triangle{
+		"some faces flipped": flipped(unitCube(), 0, 5, 9),
+		"inside out":         flipped(unitCube(), upTo(12)...),
+	} {
+		fixed := FixWinding(NewIndexedMesh(triangles, 0))
+		if ok, face := ConsistentWinding(fixed); !ok {
+			t.Errorf("%s: face %d is still inconsistent", label, face)
+		}
+		if v := signedVolume(fixed.Vertices, fixed.Faces, upTo(len(fixed.Faces))); v <= 0 {
+			t.Errorf("%s: signed volume = %v, want positive", label, v)
+		}
+	}
+
+	// Each component is oriented outward on its own
+	far := translate(flipped(unitCube(), upTo(12)...), r3.Vec{X: 5})
+	fixed := FixWinding(NewIndexedMesh(append(flipped(unitCube(), 2, 7), far...), 0))
+	for i, component := range ConnectedComponents(fixed) {
+		if v := signedVolume(fixed.Vertices, fixed.Faces, component); v <= 0 {
+			t.Errorf("component %d: signed volume = %v, want positive", i, v)
+		}
+	}
+	if ok, face := ConsistentWinding(fixed); !ok {
+		t.Errorf("two cubes: face %d is still inconsistent", face)
+	}
+}
+
+func TestConsistentWinding(t *testing.T) {
+	if ok, face := ConsistentWinding(NewIndexedMesh(unitCube(), 0)); !ok || face != -1 {
+		t.Errorf("cube: ConsistentWinding = %v, %d, want true, -1", ok, face)
+	}
+
+	// Flipped, face 4 traverses an edge of face 1 in the same direction
+	if ok, face := ConsistentWinding(NewIndexedMesh(flipped(unitCube(), 4), 0)); ok || face != 4 {
+		t.Errorf("flipped cube: ConsistentWinding = %v, %d, want false, 4", ok, face)
+	}
+}
+
+func TestIsWatertight(t *testing.T) {
+	if ok, err := IsWatertight(unitCube(), 0); !ok || err != nil {
+		t.Errorf("cube: IsWatertight = %v, %v, want true", ok, err)
+	}
+	if n := OpenEdges(unitCube(), 0); n != 0 {
+		t.Errorf("cube: OpenEdges = %d, want 0", n)
+	}
+
+	// Removing one triangle opens its three edges
+	open := unitCube()[1:]
+	if ok, err := IsWatertight(open, 0); ok || err == nil {
+		t.Errorf("open cube: IsWatertight = %v, %v, want false and an error", ok, err)
+	}
+	if n := OpenEdges(open, 0); n != 3 {
+		t.Errorf("open cube: OpenEdges = %d, want 3", n)
+	}
+
+	if _, err := IsWatertight(nil, 0); !errors.Is(err, ErrEmptyMesh) {
+		t.Errorf("IsWatertight(nil): got error %v, want ErrEmptyMesh", err)
+	}
+}
+
+func TestNonManifoldEdges(t *testing.T) {
+	if edges := NonManifoldEdges(NewIndexedMesh(unitCube(), 0)); len(edges) != 0 {
+		t.Errorf("cube: got non-manifold edges %v, want none", edges)
+	}
+
+	// Three fins share the edge from the origin to (0, 0, 1)
+	fins := []Triangle{
+		triangle(r3.Vec{}, r3.Vec{Z: 1}, r3.Vec{X: 1}),
+		triangle(r3.Vec{}, r3.Vec{Z: 1}, r3.Vec{Y: 1}),
+		triangle(r3.Vec{Z: 1}, r3.Vec{}, r3.Vec{X: -1, Y: -1}),
+	}
+	mesh := NewIndexedMesh(fins, 0)
+	edges := NonManifoldEdges(mesh)
+	if len(edges) != 1 {
+		t.Fatalf("got non-manifold edges %v, want one", edges)
+	}
+	if a, b := mesh.Vertices[edges[0][0]], mesh.Vertices[edges[0][1]]; a != (r3.Vec{}) || b != (r3.Vec{Z: 1}) {
+		t.Errorf("got non-manifold edge %v-%v, want the origin to (0, 0, 1)", a, b)
+	}
+}
+
+func TestConnectedComponents(t *testing.T) {
+	// A lone triangle first, then two cubes, the second larger
+	lone := triangle(r3.Vec{X: -5}, r3.Vec{X: -4}, r3.Vec{X: -5, Y: 1})
+	big := translate(unitCube(), r3.Vec{X: 10})
+	big = append(big, triangle(r3.Vec{X: 10}, r3.Vec{X: 10, Y: -1}, r3.Vec{X: 11}))
+
+	var triangles []Triangle
+	triangles = append(triangles, lone)
+	triangles = append(triangles, unitCube()...)
+	triangles = append(triangles, big...)
+
+	components := ConnectedComponents(NewIndexedMesh(triangles, 0))
+	want := [][]int{upTo(26)[13:], upTo(13)[1:], {0}}
+	if len(components) != len(want) {
+		t.Fatalf("got %d components, want %d", len(components), len(want))
+	}
+	for i := range want {
+		if !slices.Equal(components[i], want[i]) {
+			t.Errorf("component %d: got faces %v, want %v", i, components[i], want[i])
+		}
+	}
+
+	// Equal-sized components keep file order
+	two := ConnectedComponents(NewIndexedMesh(append(unitCube(), translate(unitCube(), r3.Vec{Y: 3})...), 0))
+	if len(two) != 2 || !slices.Equal(two[0], upTo(12)) || !slices.Equal(two[1], upTo(24)[12:]) {
+		t.Errorf("two cubes: got components %v, want faces 0-11 then 12-23", two)
+	}
+}
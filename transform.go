@@ -0,0 +1,151 @@
+package stl
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// TransformTriangles returns a copy of the given triangles with the 4x4
+// homogeneous affine transform m applied to every vertex. Normals are
+// transformed with the inverse-transpose of the linear part of m and
+// renormalized, so non-uniform scales are handled correctly. Transforms that
+// mirror the mesh also swap two vertices of each triangle to keep the
+// winding consistent with the normal. Normals become zero if m is singular.
+// It returns an error if m is not 4x4: transforms often come from scene files
+// or user input, where a malformed matrix should be reported rather than
+// crash the caller the way gonum's dimension panics would.
+func TransformTriangles(triangles []Triangle, m *mat.Dense) ([]Triangle, error) {
+	t, err := newAffine(m)
+	if err != nil {
+		return nil, err
+	}
+	return t.applyAll(triangles), nil
+}
+
+// NormalizeToUnitCube returns a copy of the given triangles scaled uniformly
+// so their longest bounding box dimension is 1 and translated so their box is
+// centered in the unit cube [0, 1]^3, e.g. for rendering previews. It also
+// returns the applied 4x4 transform, which can be inverted to map the result
+// back. Meshes with no extent are only translated.
+func NormalizeToUnitCube(triangles []Triangle) ([]Triangle, *mat.Dense) {
+	scale, center := 1.0, r3.Vec{}
+	if len(triangles) > 0 {
+		bb := boundingBoxF64FromTriangles(triangles)
+		d := bb.Dimensions()
+		if longest := max(d.X, d.Y, d.Z); longest > 0 {
+			scale = 1 / longest
+		}
+		center = bb.Center
+	}
+
+	// p' = scale*(p - center) + (0.5, 0.5, 0.5)
+	offset := r3.Sub(r3.Vec{X: 0.5, Y: 0.5, Z: 0.5}, r3.Scale(scale, center))
+	m := mat.NewDense(4, 4, []float64{
+		scale, 0, 0, offset.X,
+		0, scale, 0, offset.Y,
+		0, 0, scale, offset.Z,
+		0, 0, 0, 1,
+	})
+	t, _ := newAffine(m)
+	return t.applyAll(triangles), m
+}
+
+// Transform returns the tightest axis-aligned box containing the eight corners
+// of bb transformed by the 4x4 homogeneous affine transform m, which moves a
+// box through a scene graph without re-reading its geometry. Under rotation
+// the result is generally larger than the box of the transformed mesh.
+// Empty boxes are returned unchanged. As with TransformTriangles, an m that
+// is not 4x4 is reported as an error instead of a panic.
+func (bb *BoundingBox) Transform(m *mat.Dense) (*BoundingBox, error) {
+	t, err := newAffine(m)
+	if err != nil {
+		return nil, err
+	}
+	if bb.IsEmpty() {
+		transformed := *bb
+		return &transformed, nil
+	}
+
+	corners := bb.Corners()
+	for i := range corners {
+		corners[i] = t.applyPoint(corners[i])
+	}
+
+	transformed := newBoundingBox()
+	updateBoundingBox(transformed, corners[:])
+	updateCenter(transformed)
+	return transformed, nil
+}
+
+// affine is a 4x4 homogeneous transform prepared for applying to triangles
+type affine struct {
+	linear      *r3.Mat
+	translation r3.Vec
+	// normal is the inverse-transpose of linear, or nil if linear is singular
+	normal *r3.Mat
+	// mirrored reports whether the transform flips orientation
+	mirrored bool
+}
+
+// newAffine prepares the 4x4 homogeneous transform m, returning an error if m
+// is nil or has another shape
+func newAffine(m *mat.Dense) (*affine, error) {
+	if m == nil {
+		return nil, fmt.Errorf("invalid transform: matrix is nil")
+	}
+	if r, c := m.Dims(); r != 4 || c != 4 {
+		return nil, fmt.Errorf("invalid transform: got %dx%d matrix, must be 4x4", r, c)
+	}
+
+	linear := mat.NewDense(3, 3, nil)
+	linear.Copy(m.Slice(0, 3, 0, 3))
+
+	t := &affine{
+		linear:      r3.NewMat(linear.RawMatrix().Data),
+		translation: r3.Vec{X: m.At(0, 3), Y: m.At(1, 3), Z: m.At(2, 3)},
+		mirrored:    mat.Det(linear) < 0,
+	}
+
+	var inverse mat.Dense
+	if err := inverse.Inverse(linear); err == nil {
+		t.normal = r3.NewMat(mat.DenseCopyOf(inverse.T()).RawMatrix().Data)
+	}
+
+	return t, nil
+}
+
+// applyAll returns a copy of triangles with the transform applied
+func (t *affine) applyAll(triangles []Triangle) []Triangle {
+	result := make([]Triangle, len(triangles))
+	for i := range triangles {
+		result[i] = t.apply(triangles[i])
+	}
+	return result
+}
+
+// applyPoint transforms a single point
+func (t *affine) applyPoint(p r3.Vec) r3.Vec {
+	return r3.Add(t.linear.MulVec(p), t.translation)
+}
+
+// apply transforms a triangle's vertices and normal
+func (t *affine) apply(tri Triangle) Triangle {
+	var out Triangle
+	for j, v := range tri.Vertices {
+		out.Vertices[j] = t.applyPoint(v)
+	}
+	if t.mirrored {
+		out.Vertices[1], out.Vertices[2] = out.Vertices[2], out.Vertices[1]
+	}
+
+	if t.normal != nil {
+		n := t.normal.MulVec(tri.Normal)
+		if norm := r3.Norm(n); norm > 0 {
+			out.Normal = r3.Scale(1/norm, n)
+		}
+	}
+
+	return out
+}
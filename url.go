@@ -0,0 +1,30 @@
+package stl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CalculateBoundingBoxFromURL fetches an STL file over HTTP and returns its
+// bounding box, streaming the response body without buffering it to disk.
+// Responses other than 200 OK are reported as errors. Cancelling ctx aborts
+// both the request and parsing.
+func CalculateBoundingBoxFromURL(ctx context.Context, url string, opts ...Option) (*BoundingBox, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s: unexpected status %s", url, resp.Status)
+	}
+
+	return CalculateBoundingBoxContext(ctx, resp.Body, opts...)
+}
@@ -0,0 +1,44 @@
+package stl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCalculateBoundingBoxFromURL(t *testing.T) {
+	data := binarySTL(t, unitCube())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cube.stl" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	want, err := CalculateBoundingBox(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("CalculateBoundingBox: %v", err)
+	}
+	got, err := CalculateBoundingBoxFromURL(context.Background(), server.URL+"/cube.stl")
+	if err != nil {
+		t.Fatalf("CalculateBoundingBoxFromURL: %v", err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("got box %v, want %v", got, want)
+	}
+
+	if _, err := CalculateBoundingBoxFromURL(context.Background(), server.URL+"/missing.stl"); err == nil {
+		t.Errorf("404 response: got no error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CalculateBoundingBoxFromURL(ctx, server.URL+"/cube.stl"); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context: got error %v, want context.Canceled", err)
+	}
+}
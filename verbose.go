@@ -0,0 +1,74 @@
+package stl
+
+import (
+	"io"
+	"time"
+)
+
+// ParseInfo reports how an STL file was parsed
+type ParseInfo struct {
+	// BytesRead is the number of bytes the parser consumed, including any
+	// triangles dropped by options such as WithSkipNonFinite. Input after the
+	// point where parsing stopped, such as trailing data or the rest of a file
+	// cut short by WithTriangleLimit, is not counted.
+	BytesRead int64
+	// Duration is the wall-clock time spent parsing
+	Duration time.Duration
+	// Format is the format the file was parsed as, or FormatUnknown if
+	// parsing failed before reaching the first solid
+	Format Format
+}
+
+// CalculateBoundingBoxVerbose is like CalculateBoundingBox but also returns
+// how many bytes were read, how long parsing took, and which format was
+// parsed. The info is returned even on error, covering the work done before
+// the failure. WithParallel has no effect, as the bytes are counted as a
+// single parser consumes them.
+func CalculateBoundingBoxVerbose(r io.Reader, opts ...Option) (*BoundingBox, *ParseInfo, error) {
+	cfg := newConfig(opts)
+	cfg.parallel = false
+	cfg.consumed = &countingReader{}
+
+	info := &ParseInfo{}
+	bbox := newBoundingBox()
+
+	start := time.Now()
+	err := parse(r, cfg, &visitor{
+		solid: func(_ string, count int) {
+			// Only binary files declare their triangle count
+			if count >= 0 {
+				info.Format = FormatBinary
+			} else {
+				info.Format = FormatASCII
+			}
+		},
+		triangle: func(t Triangle) error {
+			updateBoundingBox(bbox, t.Vertices[:])
+			return nil
+		},
+	})
+	info.Duration = time.Since(start)
+
+	info.BytesRead = cfg.consumed.n
+
+	if err != nil {
+		return nil, info, err
+	}
+
+	updateCenter(bbox)
+	bbox.Partial = cfg.partial
+	return bbox, info, nil
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read reads from the underlying reader, counting the bytes read
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,44 @@
+package stl
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestVerboseBytesRead(t *testing.T) {
+	triangles := strip(10)
+	triangles[3].Vertices[0].X = math.NaN()
+	triangles[7].Vertices[2].Z = math.Inf(-1)
+
+	binaryData := binarySTL(t, triangles)
+	asciiData := asciiSTL(t, "strip", triangles)
+	// Parsing stops right after the last triangle within the limit
+	asciiLimited := asciiSTL(t, "strip", strip(2))
+	asciiLimited = asciiLimited[:bytes.LastIndex(asciiLimited, []byte("endfacet"))+len("endfacet")]
+
+	tests := []struct {
+		name string
+		data []byte
+		opts []Option
+		want int64
+	}{
+		{"binary skipping non-finite", binaryData, []Option{WithSkipNonFinite(true)}, int64(len(binaryData))},
+		{"binary with trailing bytes", append(bytes.Clone(binaryData), make([]byte, 200)...), []Option{WithSkipNonFinite(true)}, int64(len(binaryData))},
+		{"binary limited", binaryData, []Option{WithTriangleLimit(2)}, binaryFileSize(2)},
+		{"ascii skipping non-finite", asciiData, []Option{WithSkipNonFinite(true)}, int64(len(asciiData))},
+		{"ascii limited", asciiSTL(t, "strip", strip(10)), []Option{WithTriangleLimit(2)}, int64(len(asciiLimited))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, info, err := CalculateBoundingBoxVerbose(bytes.NewReader(tt.data), tt.opts...)
+			if err != nil {
+				t.Fatalf("CalculateBoundingBoxVerbose: %v", err)
+			}
+			if info.BytesRead != tt.want {
+				t.Errorf("BytesRead = %d, want %d", info.BytesRead, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package stl
+
+import (
+	"io"
+)
+
+// Warning describes a non-fatal problem encountered while parsing, such as a
+// malformed facet skipped by WithSkipInvalid
+type Warning struct {
+	// Line is the line of the ASCII file where the problem was found
+	Line int
+	// Err describes the problem, and wraps ErrInvalidVertex for skipped facets
+	Err error
+}
+
+// String returns the warning's message
+func (w Warning) String() string {
+	return w.Err.Error()
+}
+
+// CalculateBoundingBoxWithWarnings is like CalculateBoundingBox but also
+// returns the warnings collected while parsing. Combined with
+// WithSkipInvalid(true), a file with a few malformed facets yields the box of
+// its remaining facets along with one warning per skipped facet.
+func CalculateBoundingBoxWithWarnings(r io.Reader, opts ...Option) (*BoundingBox, []Warning, error) {
+	var warnings []Warning
+	cfg := newConfig(opts)
+	cfg.warnings = &warnings
+
+	bbox := &BoundingBox{}
+	if err := calculateBoundingBox(r, cfg, bbox); err != nil {
+		return nil, warnings, err
+	}
+	return bbox, warnings, nil
+}
@@ -0,0 +1,120 @@
+package stl
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// weldKey identifies a cell of the welding grid
+type weldKey [3]int64
+
+// vertexWelder merges vertices within a tolerance of each other into a shared,
+// indexed vertex list. Vertices are bucketed in a spatial hash grid with cells
+// as wide as the tolerance, so each lookup only inspects neighboring cells.
+type vertexWelder struct {
+	tol      float64
+	grid     map[weldKey][]int
+	exact    map[r3.Vec]int
+	vertices []r3.Vec
+}
+
+// newVertexWelder returns a welder merging vertices within tol of each other.
+// A tol of 0 or less only merges exactly equal vertices.
+func newVertexWelder(tol float64) *vertexWelder {
+	w := &vertexWelder{tol: tol}
+	if tol > 0 {
+		w.grid = make(map[weldKey][]int)
+	} else {
+		w.exact = make(map[r3.Vec]int)
+	}
+	return w
+}
+
+// add returns the index of the welded vertex for v, adding it if it is new.
+// A vertex is welded to the first existing vertex found within the tolerance.
+func (w *vertexWelder) add(v r3.Vec) int {
+	if i, ok := w.find(v); ok {
+		return i
+	}
+
+	if w.tol <= 0 {
+		w.exact[v] = len(w.vertices)
+	} else {
+		key := w.cell(v)
+		w.grid[key] = append(w.grid[key], len(w.vertices))
+	}
+	w.vertices = append(w.vertices, v)
+	return len(w.vertices) - 1
+}
+
+// find returns the index of the first vertex found within the tolerance of v
+func (w *vertexWelder) find(v r3.Vec) (int, bool) {
+	if w.tol <= 0 {
+		i, ok := w.exact[v]
+		return i, ok
+	}
+
+	key := w.cell(v)
+	tol2 := w.tol * w.tol
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for dz := int64(-1); dz <= 1; dz++ {
+				for _, i := range w.grid[weldKey{key[0] + dx, key[1] + dy, key[2] + dz}] {
+					if r3.Norm2(r3.Sub(w.vertices[i], v)) <= tol2 {
+						return i, true
+					}
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// cell returns the grid cell containing v
+func (w *vertexWelder) cell(v r3.Vec) weldKey {
+	return weldKey{
+		int64(math.Floor(v.X / w.tol)),
+		int64(math.Floor(v.Y / w.tol)),
+		int64(math.Floor(v.Z / w.tol)),
+	}
+}
+
+// UniqueVertices returns the number of distinct vertices among the given
+// triangles after welding vertices within tol of each other. A tol of 0 or
+// less only merges exactly equal vertices.
+func UniqueVertices(triangles []Triangle, tol float64) int {
+	welder := newVertexWelder(tol)
+	for i := range triangles {
+		for _, v := range triangles[i].Vertices {
+			welder.add(v)
+		}
+	}
+	return len(welder.vertices)
+}
+
+// RemoveDuplicateTriangles returns the given triangles without repeated
+// facets, keeping the first copy of each, along with the number removed.
+// Triangles are duplicates if their vertices weld together within tol,
+// regardless of vertex order or winding. A tol of 0 or less only matches
+// exactly equal vertices.
+func RemoveDuplicateTriangles(triangles []Triangle, tol float64) ([]Triangle, int) {
+	welder := newVertexWelder(tol)
+	seen := make(map[[3]int]struct{}, len(triangles))
+	unique := make([]Triangle, 0, len(triangles))
+	for i := range triangles {
+		var key [3]int
+		for j, v := range triangles[i].Vertices {
+			key[j] = welder.add(v)
+		}
+		sort.Ints(key[:])
+
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, triangles[i])
+	}
+	return unique, len(triangles) - len(unique)
+}
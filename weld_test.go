@@ -0,0 +1,26 @@
+package stl
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestUniqueVertices(t *testing.T) {
+	if n := UniqueVertices(unitCube(), 0); n != 8 {
+		t.Errorf("UniqueVertices(unit cube, 0) = %d, want 8", n)
+	}
+
+	noisy := unitCube()
+	noisy[3].Vertices[1] = r3.Add(noisy[3].Vertices[1], r3.Vec{Z: 1e-9})
+	if n := UniqueVertices(noisy, 0); n != 9 {
+		t.Errorf("exact: UniqueVertices = %d, want 9", n)
+	}
+	if n := UniqueVertices(noisy, 1e-6); n != 8 {
+		t.Errorf("within 1e-6: UniqueVertices = %d, want 8", n)
+	}
+
+	if n := UniqueVertices(nil, 0); n != 0 {
+		t.Errorf("UniqueVertices(nil, 0) = %d, want 0", n)
+	}
+}
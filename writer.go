@@ -0,0 +1,236 @@
+package stl
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// WriteASCII writes the given triangles to w as an ASCII STL document named name.
+// Coordinates are written with full float64 precision. Triangles with a zero
+// Normal have their facet normal recomputed from the vertex winding.
+func WriteASCII(w io.Writer, name string, triangles []Triangle) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "solid %s\n", name)
+	for i := range triangles {
+		t := &triangles[i]
+
+		normal := t.Normal
+		if normal == (r3.Vec{}) {
+			normal = faceNormal(t.Vertices)
+		}
+
+		fmt.Fprintf(bw, "  facet normal %g %g %g\n", normal.X, normal.Y, normal.Z)
+		fmt.Fprintf(bw, "    outer loop\n")
+		for _, v := range t.Vertices {
+			fmt.Fprintf(bw, "      vertex %g %g %g\n", v.X, v.Y, v.Z)
+		}
+		fmt.Fprintf(bw, "    endloop\n")
+		fmt.Fprintf(bw, "  endfacet\n")
+	}
+	fmt.Fprintf(bw, "endsolid %s\n", name)
+
+	// bufio.Writer retains the first write error, so checking Flush is enough
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	return nil
+}
+
+// WriteBinary writes the given triangles to w as a little-endian binary STL file
+// with a zeroed 80-byte header and zero attribute byte counts. Coordinates and
+// normals are stored as float32. Triangles with a zero Normal have their facet
+// normal recomputed from the vertex winding.
+func WriteBinary(w io.Writer, triangles []Triangle) error {
+	if uint64(len(triangles)) > math.MaxUint32 {
+		return fmt.Errorf("too many triangles for binary STL: %d", len(triangles))
+	}
+
+	bw := bufio.NewWriter(w)
+
+	var header [84]byte
+	binary.LittleEndian.PutUint32(header[80:], uint32(len(triangles)))
+	bw.Write(header[:])
+
+	var record [50]byte
+	for i := range triangles {
+		encodeBinaryTriangle(record[:], &triangles[i])
+		bw.Write(record[:])
+	}
+
+	// bufio.Writer retains the first write error, so checking Flush is enough
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	return nil
+}
+
+// StreamTransform reads triangles from in one at a time, applies the 4x4
+// affine transform m as TransformTriangles would, and writes them to out as a
+// binary STL file, so memory use stays flat regardless of mesh size.
+//
+// The triangle count in the binary header is not known until the input is
+// exhausted. If out implements io.WriteSeeker and can report its position,
+// the count is patched in at the end and out is left positioned after the
+// data; otherwise in must implement io.Seeker so the triangles can be counted
+// in a first pass, which applies opts as the second does but reports no
+// progress. It returns an error without reading or writing anything if m is
+// not 4x4.
+func StreamTransform(in io.Reader, out io.Writer, m *mat.Dense, opts ...Option) error {
+	t, err := newAffine(m)
+	if err != nil {
+		return err
+	}
+
+	// Some io.WriteSeekers, such as an *os.File on a pipe, cannot actually
+	// seek; finding the position up front tells those apart
+	seeker, patch := out.(io.WriteSeeker)
+	var start int64
+	if patch {
+		start, err = seeker.Seek(0, io.SeekCurrent)
+		patch = err == nil
+	}
+	declared := -1
+	if !patch {
+		if declared, err = countThenRewind(in, opts); err != nil {
+			return err
+		}
+	}
+
+	bw := bufio.NewWriter(out)
+
+	var header [80]byte
+	bw.Write(header[:])
+	binary.Write(bw, binary.LittleEndian, uint32(max(declared, 0)))
+
+	count := 0
+	var record [50]byte
+	err = ForEachTriangle(in, func(tri Triangle) error {
+		if uint64(count) >= math.MaxUint32 {
+			return fmt.Errorf("too many triangles for binary STL: more than %d", uint64(math.MaxUint32))
+		}
+		transformed := t.apply(tri)
+		encodeBinaryTriangle(record[:], &transformed)
+		bw.Write(record[:])
+		count++
+		return nil
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	// bufio.Writer retains the first write error, so checking Flush is enough
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	if !patch {
+		if count != declared {
+			return fmt.Errorf("triangle count changed between passes: counted %d, wrote %d", declared, count)
+		}
+		return nil
+	}
+
+	var raw [4]byte
+	binary.LittleEndian.PutUint32(raw[:], uint32(count))
+	if _, err := seeker.Seek(start+80, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking output: %w", err)
+	}
+	if _, err := seeker.Write(raw[:]); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	if _, err := seeker.Seek(start+binaryFileSize(uint32(count)), io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking output: %w", err)
+	}
+	return nil
+}
+
+// countThenRewind counts the triangles of the STL file in r that parsing with
+// opts yields and seeks r back to where it started. r must implement
+// io.Seeker.
+func countThenRewind(r io.Reader, opts []Option) (int, error) {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return 0, errors.New("streaming to a non-seekable writer requires a seekable reader")
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, fmt.Errorf("error seeking file: %w", err)
+	}
+	// Count with the same options as the writing pass, so limits and skipped
+	// triangles agree, but report progress only once
+	cfg := newConfig(opts)
+	cfg.progress = nil
+	count := 0
+	err = parse(r, cfg, &visitor{triangle: func(Triangle) error {
+		count++
+		return nil
+	}})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("error seeking file: %w", err)
+	}
+	return count, nil
+}
+
+// encodeBinaryTriangle encodes t into a 50-byte little-endian binary STL
+// record with a zero attribute byte count. A zero Normal is recomputed from
+// the vertex winding. Reusing record avoids the reflection of binary.Write.
+func encodeBinaryTriangle(record []byte, t *Triangle) {
+	normal := t.Normal
+	if normal == (r3.Vec{}) {
+		normal = faceNormal(t.Vertices)
+	}
+
+	encodeBinaryVec(record[0:12], normal)
+	for j, v := range t.Vertices {
+		encodeBinaryVec(record[12+12*j:24+12*j], v)
+	}
+	binary.LittleEndian.PutUint16(record[48:50], 0)
+}
+
+// encodeBinaryVec encodes v as three little-endian float32 values
+func encodeBinaryVec(b []byte, v r3.Vec) {
+	binary.LittleEndian.PutUint32(b[0:4], math.Float32bits(float32(v.X)))
+	binary.LittleEndian.PutUint32(b[4:8], math.Float32bits(float32(v.Y)))
+	binary.LittleEndian.PutUint32(b[8:12], math.Float32bits(float32(v.Z)))
+}
+
+// WriteOBJ writes the given triangles to w as a Wavefront OBJ file.
+// Exactly coincident vertices are shared between faces.
+func WriteOBJ(w io.Writer, triangles []Triangle) error {
+	return WriteOBJWithTolerance(w, triangles, 0)
+}
+
+// WriteOBJWithTolerance writes the given triangles to w as a Wavefront OBJ file,
+// welding vertices within tol of each other into a single shared vertex.
+// A tol of 0 or less only welds exactly coincident vertices.
+func WriteOBJWithTolerance(w io.Writer, triangles []Triangle, tol float64) error {
+	mesh := NewIndexedMesh(triangles, tol)
+
+	bw := bufio.NewWriter(w)
+
+	for _, v := range mesh.Vertices {
+		fmt.Fprintf(bw, "v %g %g %g\n", v.X, v.Y, v.Z)
+	}
+	// OBJ indices are 1-based
+	for _, f := range mesh.Faces {
+		fmt.Fprintf(bw, "f %d %d %d\n", f[0]+1, f[1]+1, f[2]+1)
+	}
+
+	// bufio.Writer retains the first write error, so checking Flush is enough
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// identity4 returns the 4x4 identity transform
+func identity4() *mat.Dense {
+	return mat.NewDense(4, 4, []float64{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	})
+}
+
+// bigEndian converts a little-endian binary STL file to big-endian
+func bigEndian(data []byte) []byte {
+	out := bytes.Clone(data)
+	binary.BigEndian.PutUint32(out[80:84], binary.LittleEndian.Uint32(data[80:84]))
+	for offset := 84; offset+50 <= len(out); offset += 50 {
+		for k := 0; k < 48; k += 4 {
+			binary.BigEndian.PutUint32(out[offset+k:], binary.LittleEndian.Uint32(data[offset+k:]))
+		}
+		binary.BigEndian.PutUint16(out[offset+48:], binary.LittleEndian.Uint16(data[offset+48:]))
+	}
+	return out
+}
+
+func TestStreamTransformOptions(t *testing.T) {
+	a := triangle(r3.Vec{}, r3.Vec{X: 1}, r3.Vec{Y: 1})
+	b := triangle(r3.Vec{Z: 1}, r3.Vec{X: 2, Z: 1}, r3.Vec{Y: 2, Z: 1})
+	bad := triangle(r3.Vec{X: math.NaN()}, r3.Vec{X: 1}, r3.Vec{Y: 1})
+
+	invalidASCII := []byte(`solid part
+facet normal 0 0 1
+ outer loop
+  vertex 0 0 0
+  vertex 1 0 0
+  vertex 0 1 0
+ endloop
+endfacet
+facet normal 0 0 1
+ outer loop
+  vertex 0 0 x
+  vertex 1 0 0
+  vertex 0 1 0
+ endloop
+endfacet
+endsolid part
+`)
+
+	tests := []struct {
+		name  string
+		input []byte
+		opts  []Option
+		want  int
+	}{
+		{"triangle limit", binarySTL(t, []Triangle{a, b}), []Option{WithTriangleLimit(1)}, 1},
+		{"skip non-finite", binarySTL(t, []Triangle{a, bad, b}), []Option{WithSkipNonFinite(true)}, 2},
+		{"skip invalid", invalidASCII, []Option{WithSkipInvalid(true)}, 1},
+		{"endianness", bigEndian(binarySTL(t, []Triangle{a, b})), []Option{WithEndianness(binary.BigEndian), WithFormat(FormatBinary)}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := ParseSTL(bytes.NewReader(tt.input), tt.opts...)
+			if err != nil {
+				t.Fatalf("ParseSTL: %v", err)
+			}
+			if len(want) != tt.want {
+				t.Fatalf("ParseSTL returned %d triangles, want %d", len(want), tt.want)
+			}
+
+			// A bytes.Buffer cannot seek, so the input is counted first
+			var streamed bytes.Buffer
+			if err := StreamTransform(bytes.NewReader(tt.input), &streamed, identity4(), tt.opts...); err != nil {
+				t.Fatalf("StreamTransform to a buffer: %v", err)
+			}
+
+			// A file can seek, so the count is patched in afterwards
+			file, err := os.Create(filepath.Join(t.TempDir(), "out.stl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			if err := StreamTransform(bytes.NewReader(tt.input), file, identity4(), tt.opts...); err != nil {
+				t.Fatalf("StreamTransform to a file: %v", err)
+			}
+			patched, err := os.ReadFile(file.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(streamed.Bytes(), patched) {
+				t.Errorf("buffered and patched output differ")
+			}
+			if !bytes.Equal(streamed.Bytes(), binarySTL(t, want)) {
+				t.Errorf("output differs from WriteBinary of the parsed triangles")
+			}
+		})
+	}
+}
+
+// unseekableWriter is an io.WriteSeeker whose Seek always fails, like an
+// *os.File on a pipe
+type unseekableWriter struct {
+	bytes.Buffer
+}
+
+func (w *unseekableWriter) Seek(int64, int) (int64, error) {
+	return 0, errors.New("illegal seek")
+}
+
+func TestStreamTransformUnseekableWriter(t *testing.T) {
+	input := binarySTL(t, unitCube())
+
+	// The output cannot seek, so the seekable input is counted first instead
+	var out unseekableWriter
+	if err := StreamTransform(bytes.NewReader(input), &out, identity4()); err != nil {
+		t.Fatalf("StreamTransform: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), input) {
+		t.Errorf("output differs from the input under the identity transform")
+	}
+
+	// With neither side seekable there is no way to learn the count
+	out.Reset()
+	if err := StreamTransform(bytes.NewBuffer(input), &out, identity4()); err == nil {
+		t.Errorf("StreamTransform from an unseekable reader: got no error")
+	}
+}
+
+func TestWriteBinaryRoundTrip(t *testing.T) {
+	triangles := strip(5)
+	triangles[0].Normal = r3.Vec{Z: 1}
+	// float32 cannot hold this exactly, so it is rounded on write
+	triangles[1].Vertices[2].Y = 1.0 / 3
+
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, triangles); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	data := buf.Bytes()
+	if len(data) != int(binaryFileSize(uint32(len(triangles)))) {
+		t.Fatalf("wrote %d bytes, want %d", len(data), binaryFileSize(uint32(len(triangles))))
+	}
+
+	var got []Triangle
+	err := parseBinary(bytes.NewReader(data), newConfig(nil), int64(len(data)), &visitor{
+		attribute: func(a uint16) {
+			if a != 0 {
+				t.Errorf("attribute byte count %d, want 0", a)
+			}
+		},
+		triangle: func(tri Triangle) error {
+			got = append(got, tri)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("parseBinary: %v", err)
+	}
+	if len(got) != len(triangles) {
+		t.Fatalf("read %d triangles, want %d", len(got), len(triangles))
+	}
+
+	for i := range triangles {
+		normal := triangles[i].Normal
+		if normal == (r3.Vec{}) {
+			normal = faceNormal(triangles[i].Vertices)
+		}
+		if want := float32Vec(normal); got[i].Normal != want {
+			t.Errorf("triangle %d: normal %v, want %v", i, got[i].Normal, want)
+		}
+		for j, v := range triangles[i].Vertices {
+			if want := float32Vec(v); got[i].Vertices[j] != want {
+				t.Errorf("triangle %d vertex %d: got %v, want %v", i, j, got[i].Vertices[j], want)
+			}
+		}
+	}
+}
+
+// float32Vec rounds each coordinate of v to float32 precision
+func float32Vec(v r3.Vec) r3.Vec {
+	return r3.Vec{X: float64(float32(v.X)), Y: float64(float32(v.Y)), Z: float64(float32(v.Z))}
+}
+
+func TestWriteASCIIRoundTrip(t *testing.T) {
+	triangles := strip(5)
+	for i := range triangles {
+		triangles[i].Normal = faceNormal(triangles[i].Vertices)
+	}
+	// Full float64 precision survives the text encoding
+	triangles[1].Vertices[2].Y = 1.0 / 3
+	triangles[2].Vertices[0].Z = -1e-300
+
+	data := asciiSTL(t, "part", triangles)
+
+	solids, err := ParseSolids(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseSolids: %v", err)
+	}
+	got, ok := solids["part"]
+	if !ok || len(solids) != 1 {
+		t.Fatalf("got solids %v, want one named part", solids)
+	}
+	if len(got) != len(triangles) {
+		t.Fatalf("read %d triangles, want %d", len(got), len(triangles))
+	}
+	for i := range triangles {
+		if got[i] != triangles[i] {
+			t.Errorf("triangle %d: got %+v, want %+v", i, got[i], triangles[i])
+		}
+	}
+}
+
+func TestTransformShape(t *testing.T) {
+	cube := unitCube()
+	box := BoundingBoxFromTriangles(cube)
+
+	for _, m := range []*mat.Dense{mat.NewDense(3, 3, nil), mat.NewDense(4, 3, nil), nil} {
+		if _, err := TransformTriangles(cube, m); err == nil {
+			t.Errorf("TransformTriangles: got nil error for a non-4x4 matrix")
+		}
+		if _, err := box.Transform(m); err == nil {
+			t.Errorf("BoundingBox.Transform: got nil error for a non-4x4 matrix")
+		}
+		var out bytes.Buffer
+		if err := StreamTransform(bytes.NewReader(binarySTL(t, cube)), &out, m); err == nil || out.Len() != 0 {
+			t.Errorf("StreamTransform: got error %v after writing %d bytes, want an error and no output", err, out.Len())
+		}
+	}
+
+	got, err := TransformTriangles(cube, identity4())
+	if err != nil {
+		t.Fatalf("TransformTriangles: %v", err)
+	}
+	if !BoundingBoxFromTriangles(got).Equal(box) {
+		t.Errorf("identity transform changed the box")
+	}
+}
+
+func TestWriteOBJ(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOBJ(&buf, unitCube()); err != nil {
+		t.Fatalf("WriteOBJ: %v", err)
+	}
+
+	var vertices, faces int
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "v":
+			vertices++
+		case "f":
+			faces++
+			for _, field := range fields[1:] {
+				if index, err := strconv.Atoi(field); err != nil || index < 1 || index > 8 {
+					t.Errorf("face %q has index %q outside 1..8", line, field)
+				}
+			}
+		default:
+			t.Errorf("unexpected line %q", line)
+		}
+	}
+	if vertices != 8 || faces != 12 {
+		t.Errorf("got %d vertices and %d faces, want 8 and 12", vertices, faces)
+	}
+}